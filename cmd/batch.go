@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"racingMetrics/internal/batch"
+	"racingMetrics/internal/service"
+	"runtime"
+)
+
+func runBatch(ctx context.Context, w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	workers := flags.Int("workers", runtime.GOMAXPROCS(0), "races processed at once")
+	format := flags.String("format", string(service.FormatText), "resulting tables format: text or json")
+	withLog := flags.Bool("log", false, "write outgoing events of every race as JSON Lines")
+	outDir := flags.String("out", "", "output directory")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *outDir == "" {
+		return errors.New("usage: main batch [-workers n] [-format text|json] [-log] -out <dir> <archive>")
+	}
+
+	jobs, err := batch.Find(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	summaries, err := batch.Run(ctx, jobs, *outDir, batch.Options{
+		Workers: *workers,
+		Format:  service.Format(*format),
+		Log:     *withLog,
+	})
+	if err != nil {
+		return err
+	}
+
+	failed, events := 0, 0
+	for _, summary := range summaries {
+		events += summary.Events
+		if summary.Error != "" {
+			failed++
+			fmt.Fprintf(w, "%s: %s\n", summary.Name, summary.Error)
+		}
+	}
+	fmt.Fprintf(w, "Processed %d races (%d failed), %d events, index %s\n", len(summaries), failed, events, filepath.Join(*outDir, batch.IndexName))
+	return nil
+}
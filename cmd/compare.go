@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/compare"
+	"racingMetrics/internal/model"
+	"strconv"
+)
+
+func runCompare(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	format := flags.String("format", "text", "report format: text or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 3 {
+		return errors.New("usage: main compare [-format text|json] <table.json> <competitorA> <competitorB>")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	doc := classification.Document{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	results := make([]model.Result, 0, 2)
+	for _, arg := range flags.Args()[1:] {
+		runnerID, err := strconv.Atoi(arg)
+		if err != nil {
+			return err
+		}
+		result, err := compare.Find(doc, runnerID)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+	report := compare.Compare(results[0], results[1], doc.Timing)
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "text":
+		report.Write(os.Stdout)
+		return nil
+	default:
+		return errors.New("unknown report format: " + *format)
+	}
+}
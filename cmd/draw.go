@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"racingMetrics/internal/draw"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/service"
+	"racingMetrics/internal/startlist"
+	"strings"
+	"time"
+)
+
+// drawLead is how long before the race start draw events are emitted by default
+const drawLead = 30 * 60 * 1000
+
+func runDraw(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	mode := flags.String("mode", string(draw.ModeRandom), "draw mode: random, ranking or groups")
+	seed := flags.Uint64("seed", 0, "random seed, current time if 0")
+	groups := flags.Int("groups", 1, "amount of start groups in groups mode")
+	rankingPath := flags.String("ranking", "", "JSON resulting table to seed competitors by")
+	at := flags.String("at", "", "time of emitted events, 30 minutes before start by default")
+	outPath := flags.String("out", "", "start list (.csv or .json) to write, stdout CSV if empty")
+	eventsPath := flags.String("events", "", "events file to write set start time events to, stdout if empty, required without -out")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: main draw [-mode random|ranking|groups] [-seed n] [-groups n] [-ranking results.json] [-at time] [-out startlist] [-events path] <config.json> <startlist>")
+	}
+	if *outPath == "" && *eventsPath == "" {
+		// stdout is either the start list or the events file
+		return errors.New("draw: -out or -events is required, only one of the start list and the events goes to stdout")
+	}
+
+	config, err := service.LoadConfig(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	competitors, err := startlist.Load(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	opts := draw.Options{
+		Mode:   draw.Mode(*mode),
+		Seed:   *seed,
+		Groups: *groups,
+	}
+	if opts.Seed == 0 {
+		opts.Seed = uint64(time.Now().UnixNano())
+		fmt.Fprintf(w, "Draw seed: %d\n", opts.Seed)
+	}
+	if opts.Start, err = model.ParseTime(config.Start); err != nil {
+		return fmt.Errorf("config start: %w", err)
+	}
+	if opts.Interval, err = model.ParseDuration(config.StartDelta); err != nil {
+		return fmt.Errorf("config startDelta: %w", err)
+	}
+	if *rankingPath != "" {
+		if opts.Ranking, err = draw.LoadRanking(*rankingPath); err != nil {
+			return err
+		}
+	}
+	eventsAt := max(opts.Start-drawLead, 0)
+	if *at != "" {
+		if eventsAt, err = model.ParseTime(*at); err != nil {
+			return fmt.Errorf("at: %w", err)
+		}
+	}
+
+	drawn, err := draw.Draw(competitors, opts)
+	if err != nil {
+		return err
+	}
+
+	if *outPath != "" {
+		if err := startlist.Save(*outPath, drawn); err != nil {
+			return err
+		}
+	} else if err := startlist.WriteCSV(os.Stdout, drawn); err != nil {
+		return err
+	}
+
+	lines, err := draw.Events(drawn, eventsAt)
+	if err != nil {
+		return err
+	}
+	events := strings.Join(lines, "\n") + "\n"
+	if *eventsPath != "" {
+		return os.WriteFile(*eventsPath, []byte(events), 0o600)
+	}
+	_, err = io.WriteString(os.Stdout, events)
+	return err
+}
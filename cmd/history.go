@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"racingMetrics/internal/history"
+)
+
+func runHistory(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	format := flags.String("format", "csv", "history format: csv or json")
+	athlete := flags.String("athlete", "", "name of the athlete, all athletes if empty")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: main history [-format csv|json] [-athlete name] <archive>")
+	}
+
+	races, err := history.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	histories := history.Build(races)
+	if *athlete != "" {
+		filtered := histories[:0]
+		for _, h := range histories {
+			if h.Athlete.Name == *athlete || h.Athlete.String() == *athlete {
+				filtered = append(filtered, h)
+			}
+		}
+		histories = filtered
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(histories)
+	case "csv":
+		return history.WriteCSV(os.Stdout, histories)
+	default:
+		return errors.New("unknown history format: " + *format)
+	}
+}
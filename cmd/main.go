@@ -3,12 +3,18 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/journal"
+	"racingMetrics/internal/outgoing"
 	"racingMetrics/internal/service"
+	"racingMetrics/internal/startlist"
 )
 
 func main() {
@@ -23,17 +29,113 @@ func run(ctx context.Context, w io.Writer, args []string) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
-	jsonConfigPath := args[1]
-	eventsPath := args[2]
+	if len(args) > 1 {
+		switch args[1] {
+		case "draw":
+			return runDraw(w, args[1:])
+		case "simulate":
+			return runSimulate(w, args[1:])
+		case "races":
+			return runRaces(ctx, w, args[1:])
+		case "batch":
+			return runBatch(ctx, w, args[1:])
+		case "season":
+			return runSeason(w, args[1:])
+		case "history":
+			return runHistory(w, args[1:])
+		case "compare":
+			return runCompare(w, args[1:])
+		}
+	}
+	return runRace(ctx, w, args)
+}
+
+// eventLogWriter returns writer of the event log printed along with the resulting table:
+// stdout, or w for a JSON table to keep stdout a valid JSON document
+func eventLogWriter(format service.Format, w io.Writer) io.Writer {
+	if format == service.FormatJSON {
+		return w
+	}
+	return os.Stdout
+}
+
+func runRace(ctx context.Context, w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	journalPath := flags.String("journal", "", "append-only journal to restore from and record events to")
+	snapshotPath := flags.String("snapshot", "", "state snapshot to restore from and periodically rewrite")
+	snapshotEvery := flags.Int("snapshot-every", 1000, "events between snapshots")
+	startListPath := flags.String("startlist", "", "start list (.csv or .json) with competitor details")
+	format := flags.String("format", string(service.FormatText), "resulting table format: text or json")
+	input := flags.String("input", "auto", "events file format: auto, text or jsonl")
+	logPath := flags.String("log", "", "file to write outgoing events to instead of stdout")
+	logFormat := flags.String("log-format", string(outgoing.FormatText), "outgoing events format: text or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: main [-journal path] [-snapshot path] [-startlist path] [-input auto|text|jsonl] [-log path] [-log-format text|json] [-format text|json] <config.json> <events>")
+	}
+	jsonConfigPath := flags.Arg(0)
+	eventsPath := flags.Arg(1)
 
 	logger := log.New(w, "Run error", log.LstdFlags)
 	runLogService := service.NewRunLog(jsonConfigPath, eventsPath, logger)
-	runLogService.RunEvents(ctx)
+	if err := runLogService.SetFormat(service.Format(*format)); err != nil {
+		return err
+	}
+	if *input != "auto" {
+		if err := runLogService.SetInputFormat(event.Format(*input)); err != nil {
+			return err
+		}
+	}
+	if *logPath != "" {
+		sink, err := outgoing.NewFileSink(*logPath, outgoing.Format(*logFormat))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := sink.Close(); err != nil {
+				logger.Printf("Error closing log: %v", err)
+			}
+		}()
+		runLogService.SetSink(sink)
+	} else {
+		sink, err := outgoing.NewSink(eventLogWriter(service.Format(*format), w), outgoing.Format(*logFormat))
+		if err != nil {
+			return err
+		}
+		runLogService.SetSink(sink)
+	}
+	if *journalPath != "" {
+		j, err := journal.Open(*journalPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := j.Close(); err != nil {
+				logger.Printf("Error closing journal: %v", err)
+			}
+		}()
+		runLogService.SetJournal(j)
+	}
+	if *startListPath != "" {
+		competitors, err := startlist.Load(*startListPath)
+		if err != nil {
+			return err
+		}
+		runLogService.SetStartList(competitors)
+	}
+	if *snapshotPath != "" {
+		runLogService.SetSnapshots(*snapshotPath, *snapshotEvery)
+	}
+	if err := runLogService.RunEvents(ctx); err != nil {
+		return err
+	}
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
-		runLogService.PrintResultingTable()
+		return runLogService.PrintResultingTable()
 	}
-	return nil
 }
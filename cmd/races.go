@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/service"
+	"racingMetrics/internal/startlist"
+)
+
+// racesManifest lists races run together, paths are relative to the manifest
+type racesManifest struct {
+	Races []struct {
+		ID        string `json:"id"`
+		Config    string `json:"config"`
+		Events    string `json:"events"`
+		StartList string `json:"startlist,omitempty"`
+		Log       string `json:"log,omitempty"`
+	} `json:"races"`
+}
+
+func runRaces(ctx context.Context, w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	format := flags.String("format", string(service.FormatText), "resulting tables format: text or json")
+	logFormat := flags.String("log-format", string(outgoing.FormatText), "outgoing events format: text or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: main races [-log-format text|json] [-format text|json] <races.json>")
+	}
+	manifestPath := flags.Arg(0)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	manifest := racesManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+	dir := filepath.Dir(manifestPath)
+	path := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	combined, err := outgoing.NewSink(eventLogWriter(service.Format(*format), w), outgoing.Format(*logFormat))
+	if err != nil {
+		return err
+	}
+	manager := service.NewManager(combined)
+	if err := manager.SetFormat(service.Format(*format)); err != nil {
+		return err
+	}
+
+	logger := log.New(w, "Run error", log.LstdFlags)
+	for _, r := range manifest.Races {
+		config, err := service.LoadConfig(path(r.Config))
+		if err != nil {
+			return fmt.Errorf("race %s: loading config: %w", r.ID, err)
+		}
+		race, err := service.NewEventLogger(config, logger)
+		if err != nil {
+			return fmt.Errorf("race %s: %w", r.ID, err)
+		}
+		race.SetEventsPath(path(r.Events))
+		race.SetSink(outgoing.Discard)
+		if r.Log != "" {
+			sink, err := outgoing.NewFileSink(path(r.Log), outgoing.Format(*logFormat))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := sink.Close(); err != nil {
+					logger.Printf("Error closing log of race %s: %v", r.ID, err)
+				}
+			}()
+			race.SetSink(sink)
+		}
+		if r.StartList != "" {
+			competitors, err := startlist.Load(path(r.StartList))
+			if err != nil {
+				return err
+			}
+			race.SetStartList(competitors)
+		}
+		if err := manager.Add(r.ID, race); err != nil {
+			return err
+		}
+	}
+
+	// tables of the other races are printed when one of them fails
+	err = manager.RunEvents(ctx)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return errors.Join(err, manager.PrintResultingTables(os.Stdout))
+	}
+}
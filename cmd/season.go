@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"racingMetrics/internal/season"
+	"racingMetrics/internal/service"
+)
+
+func runSeason(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	format := flags.String("format", string(service.FormatText), "standings format: text or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: main season [-format text|json] <season.json>")
+	}
+
+	config, err := season.LoadConfig(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	docs, err := season.LoadResults(config)
+	if err != nil {
+		return err
+	}
+	standings, err := season.Compute(config, docs)
+	if err != nil {
+		return err
+	}
+	switch service.Format(*format) {
+	case service.FormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(standings)
+	case service.FormatText:
+		season.Write(os.Stdout, standings)
+		return nil
+	default:
+		return errors.New("unknown output format: " + *format)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"racingMetrics/internal/service"
+	"racingMetrics/internal/simulate"
+)
+
+func runSimulate(w io.Writer, args []string) error {
+	defaults := simulate.DefaultOptions()
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	competitors := flags.Int("competitors", defaults.Competitors, "amount of competitors")
+	seed := flags.Uint64("seed", defaults.Seed, "random seed")
+	lapSpeed := flags.Float64("speed", defaults.LapSpeed, "mean ski speed, m/s")
+	lapSpeedSpread := flags.Float64("speed-spread", defaults.LapSpeedSpread, "relative standard deviation of speed")
+	penaltySpeed := flags.Float64("penalty-speed", defaults.PenaltySpeed, "mean penalty loop speed, m/s")
+	accuracy := flags.Float64("accuracy", defaults.Accuracy, "mean probability to hit a target")
+	accuracySpread := flags.Float64("accuracy-spread", defaults.AccuracySpread, "standard deviation of accuracy between competitors")
+	dnsRate := flags.Float64("dns", defaults.DNSRate, "share of competitors not starting")
+	dnfRate := flags.Float64("dnf", defaults.DNFRate, "share of competitors not finishing")
+	lateRate := flags.Float64("late", defaults.LateStartRate, "share of competitors starting late")
+	outPath := flags.String("out", "", "events file to write, stdout if empty")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: main simulate [-competitors n] [-seed n] [-speed m/s] [-accuracy p] [-dns p] [-dnf p] [-late p] [-out events] <config.json>")
+	}
+
+	config, err := service.LoadConfig(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	opts := defaults
+	opts.Competitors = *competitors
+	opts.Seed = *seed
+	opts.LapSpeed = *lapSpeed
+	opts.LapSpeedSpread = *lapSpeedSpread
+	opts.PenaltySpeed = *penaltySpeed
+	opts.Accuracy = *accuracy
+	opts.AccuracySpread = *accuracySpread
+	opts.DNSRate = *dnsRate
+	opts.DNFRate = *dnfRate
+	opts.LateStartRate = *lateRate
+
+	lines, err := simulate.Generate(config, opts)
+	if err != nil {
+		return err
+	}
+	if *outPath == "" {
+		return simulate.Write(os.Stdout, lines)
+	}
+	file, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	if err := simulate.Write(file, lines); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	return file.Close()
+}
@@ -0,0 +1,245 @@
+// Package batch reprocesses archives of races in parallel
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/service"
+	"racingMetrics/internal/startlist"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Archive layout: every directory with these files is a race
+const (
+	configName    = "config.json"
+	eventsName    = "events"
+	startListName = "startlist.csv"
+)
+
+// IndexName is the summary index in the output directory, which has a directory per race
+const IndexName = "index.json"
+
+// logName is outgoing events of a race
+const logName = "events.jsonl"
+
+// Job is a race of the archive
+type Job struct {
+	// Name is the race directory relative to the archive root
+	Name          string
+	ConfigPath    string
+	EventsPath    string
+	StartListPath string
+}
+
+// Summary is an index entry of a processed race
+type Summary struct {
+	Name        string `json:"name"`
+	Events      int    `json:"events"`
+	Competitors int    `json:"competitors"`
+	Finished    int    `json:"finished"`
+	DurationMs  int64  `json:"durationMs"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Options of batch processing
+type Options struct {
+	// Workers is amount of races processed at once, GOMAXPROCS by default
+	Workers int
+	// Format of resulting tables
+	Format service.Format
+	// Log writes outgoing events of every race as JSON Lines
+	Log bool
+}
+
+// Find returns jobs of archive directories containing config.json and events, ordered by name
+func Find(root string) ([]Job, error) {
+	var jobs []Job
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		job := Job{
+			ConfigPath: filepath.Join(path, configName),
+			EventsPath: filepath.Join(path, eventsName),
+		}
+		if !exists(job.ConfigPath) || !exists(job.EventsPath) {
+			return nil
+		}
+		if job.Name, err = filepath.Rel(root, path); err != nil {
+			return err
+		}
+		if startListPath := filepath.Join(path, startListName); exists(startListPath) {
+			job.StartListPath = startListPath
+		}
+		jobs = append(jobs, job)
+		return nil
+	})
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs, err
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// Run processes jobs by a pool of workers writing resulting table and log of every race
+// to its directory under outDir as soon as it's done, then writes the summary index.
+// A failed race is reported in its summary and doesn't stop others
+func Run(ctx context.Context, jobs []Job, outDir string, opts Options) ([]Summary, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.Format == "" {
+		opts.Format = service.FormatText
+	}
+
+	summaries := make([]Summary, len(jobs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				summaries[i] = process(jobs[i], outDir, opts)
+			}
+		}()
+	}
+feed:
+	for i := range jobs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indices <- i:
+		}
+	}
+	close(indices)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return summaries, os.WriteFile(filepath.Join(outDir, IndexName), append(data, '\n'), 0o644)
+}
+
+func process(job Job, outDir string, opts Options) Summary {
+	started := time.Now()
+	summary := Summary{Name: job.Name}
+	if err := processRace(job, filepath.Join(outDir, job.Name), opts, &summary); err != nil {
+		summary.Error = err.Error()
+	}
+	summary.DurationMs = time.Since(started).Milliseconds()
+	return summary
+}
+
+func processRace(job Job, dir string, opts Options, summary *Summary) (err error) {
+	config, err := service.LoadConfig(job.ConfigPath)
+	if err != nil {
+		return err
+	}
+	race, err := service.NewEventLogger(config, log.New(io.Discard, "", 0))
+	if err != nil {
+		return err
+	}
+	if err := race.SetFormat(opts.Format); err != nil {
+		return err
+	}
+	if job.StartListPath != "" {
+		competitors, err := startlist.Load(job.StartListPath)
+		if err != nil {
+			return err
+		}
+		race.SetStartList(competitors)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	race.SetSink(outgoing.Discard)
+	if opts.Log {
+		sink, err := outgoing.NewFileSink(filepath.Join(dir, logName), outgoing.FormatJSON)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, sink.Close())
+		}()
+		race.SetSink(sink)
+	}
+
+	if err := submitEvents(race, job.EventsPath, summary); err != nil {
+		return err
+	}
+
+	for _, result := range race.Results() {
+		summary.Competitors++
+		if result.Status == model.StatusFinished {
+			summary.Finished++
+		}
+	}
+	return writeTable(race, filepath.Join(dir, tableName(opts.Format)))
+}
+
+func submitEvents(race *service.EventLogger, path string, summary *Summary) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+	decoder, err := event.NewDecoder(file, event.FormatAuto)
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := decoder.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// events are numbered as in the file while none is rejected
+		if _, err := race.Submit(e); err != nil {
+			return fmt.Errorf("event %d at %s: %w", e.Seq, model.RawTiming.Format(e.Time), err)
+		}
+		summary.Events++
+	}
+}
+
+func tableName(format service.Format) string {
+	if format == service.FormatJSON {
+		return "table.json"
+	}
+	return "table.txt"
+}
+
+func writeTable(race *service.EventLogger, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// the file is closed on a failed write too
+	return errors.Join(race.WriteResultingTable(file), file.Close())
+}
@@ -0,0 +1,139 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/service"
+	"racingMetrics/internal/simulate"
+	"strings"
+	"testing"
+)
+
+const archive = "../service/testdata"
+
+func TestRun(t *testing.T) {
+	jobs, err := Find(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := t.TempDir()
+	if err := os.WriteFile(filepath.Join(broken, configName), []byte(`{"laps": 1, "startDelta": "00:01:30"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(broken, eventsName), []byte("[09:00:00.000] 4 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jobs = append(jobs, Job{Name: "broken", ConfigPath: filepath.Join(broken, configName), EventsPath: filepath.Join(broken, eventsName)})
+
+	out := t.TempDir()
+	summaries, err := Run(context.Background(), jobs, out, Options{Workers: 3, Log: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, summary := range summaries[:len(summaries)-1] {
+		if summary.Name != jobs[i].Name || summary.Error != "" || summary.Events == 0 || summary.Competitors == 0 {
+			t.Errorf("Unexpected summary %+v", summary)
+		}
+		got, err := os.ReadFile(filepath.Join(out, summary.Name, "table.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := os.ReadFile(filepath.Join(archive, summary.Name, "table.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Table of %s differs from the golden one", summary.Name)
+		}
+		if _, err := os.Stat(filepath.Join(out, summary.Name, logName)); err != nil {
+			t.Errorf("Expected log of %s: %v", summary.Name, err)
+		}
+	}
+	if last := summaries[len(summaries)-1]; !strings.Contains(last.Error, "no such competitor") {
+		t.Errorf("Expected broken race to fail, got %+v", last)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, IndexName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index []Summary
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != len(jobs) {
+		t.Errorf("Expected %d index entries, got %d", len(jobs), len(index))
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	jobs, err := Find(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, jobs, t.TempDir(), Options{}); err == nil {
+		t.Error("Expected canceled run to fail")
+	}
+}
+
+// BenchmarkRun reprocesses an archive of simulated races, see also
+// BenchmarkParseEvent in internal/event and BenchmarkRunner in internal/model
+func BenchmarkRun(b *testing.B) {
+	const races, competitors = 16, 200
+	root := b.TempDir()
+	config := model.Config{
+		Laps:          3,
+		LapLen:        3000,
+		PenaltyLen:    150,
+		FiringLines:   10,
+		Start:         "10:00:00.000",
+		StartDelta:    "00:00:30",
+		TargetsAmount: 5,
+	}
+	events := 0
+	for i := range races {
+		dir := filepath.Join(root, "race"+string(rune('a'+i)))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			b.Fatal(err)
+		}
+		opts := simulate.DefaultOptions()
+		opts.Competitors = competitors
+		opts.Seed = uint64(i + 1)
+		lines, err := simulate.Generate(config, opts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		events += len(lines)
+		data, err := json.Marshal(config)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, configName), data, 0o600); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, eventsName), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+			b.Fatal(err)
+		}
+	}
+	jobs, err := Find(root)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	runs := 0
+	for b.Loop() {
+		if _, err := Run(context.Background(), jobs, b.TempDir(), Options{Format: service.FormatJSON}); err != nil {
+			b.Fatal(err)
+		}
+		runs++
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(runs*events), "ns/event")
+}
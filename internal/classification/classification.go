@@ -0,0 +1,131 @@
+// Package classification ranks run results
+package classification
+
+import (
+	"fmt"
+	"io"
+	"racingMetrics/internal/model"
+	"sort"
+)
+
+// OverallName is a name of classification of all runners
+const OverallName = "Overall"
+
+// sectionTitles are headings of unranked status groups
+var sectionTitles = map[model.Status]string{
+	model.StatusDNF: "Did not finish",
+	model.StatusDNS: "Did not start",
+	model.StatusDSQ: "Disqualified",
+}
+
+// Standing is a classified result.
+// Rank is 0 for unranked runners and is shared by runners tied after timing rounding.
+// Behind and Gap are rounded times behind the leader and the previous finisher in milliseconds,
+// Time is the official time of finished runner
+type Standing struct {
+	Rank   int          `json:"rank,omitempty"`
+	Behind int          `json:"behind,omitempty"`
+	Gap    int          `json:"gap,omitempty"`
+	Time   string       `json:"time,omitempty"`
+	Result model.Result `json:"result"`
+}
+
+// Classification is a ranked list of results
+type Classification struct {
+	Name      string       `json:"name"`
+	Timing    model.Timing `json:"-"`
+	Standings []Standing   `json:"standings"`
+}
+
+// Document is a machine readable resulting table, results keep raw milliseconds
+type Document struct {
+	Timing          model.Timing     `json:"timing"`
+	Classifications []Classification `json:"classifications"`
+}
+
+// Classify orders results and ranks finished and lapped runners,
+// times are compared after timing rounding
+func Classify(name string, results []model.Result, timing model.Timing) Classification {
+	sorted := make([]model.Result, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Less(sorted[j], timing)
+	})
+
+	c := Classification{
+		Name:      name,
+		Timing:    timing,
+		Standings: make([]Standing, 0, len(sorted)),
+	}
+	for i, result := range sorted {
+		standing := Standing{Result: result}
+		if ranked(result.Status) {
+			standing.Rank = i + 1
+			if i > 0 && result.Tied(sorted[i-1], timing) {
+				standing.Rank = c.Standings[i-1].Rank
+			}
+		}
+		if result.Status == model.StatusFinished {
+			standing.Time = timing.Format(result.AdjustedTime)
+		}
+		if result.Status == model.StatusFinished && i > 0 {
+			time := timing.Round(result.AdjustedTime)
+			standing.Behind = time - timing.Round(sorted[0].AdjustedTime)
+			standing.Gap = time - timing.Round(sorted[i-1].AdjustedTime)
+		}
+		c.Standings = append(c.Standings, standing)
+	}
+	return c
+}
+
+// ByCategory returns overall classification followed by
+// classifications of every competitor category in alphabetical order
+func ByCategory(results []model.Result, timing model.Timing) []Classification {
+	categories := map[string][]model.Result{}
+	for _, result := range results {
+		if category := result.Competitor.Category; category != "" {
+			categories[category] = append(categories[category], result)
+		}
+	}
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	classifications := []Classification{Classify(OverallName, results, timing)}
+	for _, name := range names {
+		classifications = append(classifications, Classify(name, categories[name], timing))
+	}
+	return classifications
+}
+
+func ranked(status model.Status) bool {
+	return status == model.StatusFinished || status == model.StatusLapped
+}
+
+// Write writes ranked rows followed by sections of unranked status groups
+func (c Classification) Write(w io.Writer) {
+	var section model.Status
+	for _, standing := range c.Standings {
+		status := standing.Result.Status
+		if title, ok := sectionTitles[status]; ok && status != section {
+			section = status
+			fmt.Fprintln(w, title)
+		}
+		fmt.Fprintln(w, c.Row(standing))
+	}
+}
+
+// Row returns classification row: rank, result and gaps
+// behind the leader and the previous finisher
+func (c Classification) Row(s Standing) string {
+	if s.Rank == 0 {
+		return s.Result.Format(c.Timing)
+	}
+	row := fmt.Sprintf("%d %s", s.Rank, s.Result.Format(c.Timing))
+	if s.Result.Status == model.StatusFinished && s.Rank > 1 {
+		row += fmt.Sprintf(" {%s, %s}", c.Timing.FormatGap(s.Behind), c.Timing.FormatGap(s.Gap))
+	}
+	return row
+}
@@ -0,0 +1,103 @@
+package classification
+
+import (
+	"racingMetrics/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestByCategory(t *testing.T) {
+	results := []model.Result{
+		finished(1, "W", 300),
+		finished(2, "M", 100),
+		finished(3, "W", 200),
+		{RunnerID: 4, Status: model.StatusDNF, Competitor: model.Competitor{ID: 4, Category: "W"}},
+		{RunnerID: 5, Status: model.StatusDNS},
+	}
+
+	classifications := ByCategory(results, model.RawTiming)
+	if len(classifications) != 3 {
+		t.Fatalf("Expected overall and 2 categories, got %d", len(classifications))
+	}
+
+	tests := []struct {
+		name   string
+		ids    []int
+		ranks  []int
+		behind []int
+	}{
+		{OverallName, []int{2, 3, 1, 4, 5}, []int{1, 2, 3, 0, 0}, []int{0, 100, 200, 0, 0}},
+		{"M", []int{2}, []int{1}, []int{0}},
+		{"W", []int{3, 1, 4}, []int{1, 2, 0}, []int{0, 100, 0}},
+	}
+	for i, tt := range tests {
+		c := classifications[i]
+		if c.Name != tt.name {
+			t.Errorf("Expected classification %s, got %s", tt.name, c.Name)
+		}
+		if len(c.Standings) != len(tt.ids) {
+			t.Fatalf("%s: expected %d standings, got %d", tt.name, len(tt.ids), len(c.Standings))
+		}
+		for j, standing := range c.Standings {
+			if standing.Result.RunnerID != tt.ids[j] || standing.Rank != tt.ranks[j] || standing.Behind != tt.behind[j] {
+				t.Errorf("%s: expected %d rank %d behind %d, got %d rank %d behind %d", tt.name,
+					tt.ids[j], tt.ranks[j], tt.behind[j], standing.Result.RunnerID, standing.Rank, standing.Behind)
+			}
+		}
+	}
+}
+
+func TestWriteSections(t *testing.T) {
+	c := Classify(OverallName, []model.Result{
+		{RunnerID: 3, Status: model.StatusDSQ},
+		{RunnerID: 2, Status: model.StatusDNF},
+		{RunnerID: 4, Status: model.StatusDNF},
+		finished(1, "", 65432),
+	}, model.Timing{Precision: model.PrecisionTenths})
+
+	out := &strings.Builder{}
+	c.Write(out)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{"1 [00:01:05.4] 1", "Did not finish", "[DNF] 2", "[DNF] 4", "Disqualified", "[DSQ] 3"}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %q", len(want), lines)
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("Expected line %q, got %q", want[i], lines[i])
+		}
+	}
+}
+
+func TestTies(t *testing.T) {
+	c := Classify(OverallName, []model.Result{
+		finished(5, "", 1000),
+		finished(4, "", 1290),
+		finished(3, "", 1250),
+		finished(2, "", 1300),
+		finished(1, "", 1399),
+	}, model.Timing{Precision: model.PrecisionTenths})
+
+	ids := []int{5, 3, 4, 1, 2}
+	ranks := []int{1, 2, 2, 4, 4}
+	gaps := []int{0, 200, 0, 100, 0}
+	for i, standing := range c.Standings {
+		if standing.Result.RunnerID != ids[i] || standing.Rank != ranks[i] || standing.Gap != gaps[i] {
+			t.Errorf("Expected %d rank %d gap %d, got %d rank %d gap %d",
+				ids[i], ranks[i], gaps[i], standing.Result.RunnerID, standing.Rank, standing.Gap)
+		}
+	}
+	if row := c.Row(c.Standings[3]); !strings.HasSuffix(row, "{+00:00.3, +00:00.1}") {
+		t.Errorf("Unexpected row: %s", row)
+	}
+}
+
+func finished(runnerID int, category string, time int) model.Result {
+	return model.Result{
+		RunnerID:     runnerID,
+		Status:       model.StatusFinished,
+		TotalTime:    time,
+		AdjustedTime: time,
+		Competitor:   model.Competitor{ID: runnerID, Category: category},
+	}
+}
@@ -0,0 +1,187 @@
+// Package compare reports where one competitor lost or gained time against another
+package compare
+
+import (
+	"fmt"
+	"io"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const errNoCompetitor Err = "no such competitor in the race"
+
+// Segments are times of a lap in milliseconds: skiing, on the firing range and on penalty loops
+type Segments struct {
+	Ski     int `json:"ski"`
+	Range   int `json:"range"`
+	Penalty int `json:"penalty"`
+}
+
+func (s Segments) minus(other Segments) Segments {
+	return Segments{Ski: s.Ski - other.Ski, Range: s.Range - other.Range, Penalty: s.Penalty - other.Penalty}
+}
+
+func (s Segments) plus(other Segments) Segments {
+	return Segments{Ski: s.Ski + other.Ski, Range: s.Range + other.Range, Penalty: s.Penalty + other.Penalty}
+}
+
+// Lap compares a lap completed by both competitors.
+// Deltas are times of A minus times of B, positive when A was slower
+type Lap struct {
+	Lap        int      `json:"lap"`
+	A          int      `json:"a"`
+	B          int      `json:"b"`
+	Delta      int      `json:"delta"`
+	Cumulative int      `json:"cumulative"`
+	SegmentsA  Segments `json:"segmentsA"`
+	SegmentsB  Segments `json:"segmentsB"`
+	Segments   Segments `json:"segments"`
+}
+
+// Stage compares a shooting of both competitors
+type Stage struct {
+	Stage        int            `json:"stage"`
+	A            model.Shooting `json:"a"`
+	B            model.Shooting `json:"b"`
+	MissesDelta  int            `json:"missesDelta"`
+	RangeDelta   int            `json:"rangeDelta"`
+	PenaltyDelta int            `json:"penaltyDelta"`
+}
+
+// Report is a head-to-head comparison of competitor A against B, deltas are A minus B.
+// Total sums segment deltas of the compared laps, Adjustments is a delta of jury adjustments
+type Report struct {
+	A           model.Result `json:"a"`
+	B           model.Result `json:"b"`
+	Laps        []Lap        `json:"laps"`
+	Stages      []Stage      `json:"stages"`
+	Total       Segments     `json:"total"`
+	Adjustments int          `json:"adjustments"`
+	Timing      model.Timing `json:"timing"`
+}
+
+// Find returns result of the competitor from the overall classification of the race
+func Find(doc classification.Document, runnerID int) (model.Result, error) {
+	for _, c := range doc.Classifications {
+		if c.Name != classification.OverallName {
+			continue
+		}
+		for _, standing := range c.Standings {
+			if standing.Result.RunnerID == runnerID {
+				return standing.Result, nil
+			}
+		}
+	}
+	return model.Result{}, fmt.Errorf("%w: %d", errNoCompetitor, runnerID)
+}
+
+// Compare compares laps completed by both competitors and their shootings
+func Compare(a, b model.Result, timing model.Timing) Report {
+	r := Report{
+		A:           a,
+		B:           b,
+		Adjustments: adjustments(a) - adjustments(b),
+		Timing:      timing,
+	}
+	segmentsA, segmentsB := lapSegments(a), lapSegments(b)
+	for i := 0; i < len(a.Laps) && i < len(b.Laps); i++ {
+		lap := Lap{
+			Lap:       i + 1,
+			A:         a.Laps[i].Time,
+			B:         b.Laps[i].Time,
+			Delta:     a.Laps[i].Time - b.Laps[i].Time,
+			SegmentsA: segmentsA[i],
+			SegmentsB: segmentsB[i],
+			Segments:  segmentsA[i].minus(segmentsB[i]),
+		}
+		lap.Cumulative = lap.Delta
+		if i > 0 {
+			lap.Cumulative += r.Laps[i-1].Cumulative
+		}
+		r.Total = r.Total.plus(lap.Segments)
+		r.Laps = append(r.Laps, lap)
+	}
+	for i := 0; i < len(a.Shootings) || i < len(b.Shootings); i++ {
+		stage := Stage{Stage: i + 1}
+		if i < len(a.Shootings) {
+			stage.A = a.Shootings[i]
+		}
+		if i < len(b.Shootings) {
+			stage.B = b.Shootings[i]
+		}
+		stage.MissesDelta = (stage.A.Shots - stage.A.Hits) - (stage.B.Shots - stage.B.Hits)
+		stage.RangeDelta = rangeTime(stage.A) - rangeTime(stage.B)
+		stage.PenaltyDelta = stage.A.Penalty - stage.B.Penalty
+		r.Stages = append(r.Stages, stage)
+	}
+	return r
+}
+
+func adjustments(result model.Result) int {
+	sum := 0
+	for _, adjustment := range result.Adjustments {
+		sum += adjustment.Delta
+	}
+	return sum
+}
+
+func rangeTime(shooting model.Shooting) int {
+	if shooting.End == 0 {
+		return 0
+	}
+	return shooting.End - shooting.Start
+}
+
+// lapSegments splits completed laps into segments, a shooting and its penalty loops
+// belong to the lap during which the competitor came to the range
+func lapSegments(result model.Result) []Segments {
+	segments := make([]Segments, len(result.Laps))
+	lapEnd := result.StartTime
+	for i, lap := range result.Laps {
+		lapStart := lapEnd
+		lapEnd += lap.Time
+		for _, shooting := range result.Shootings {
+			if shooting.Start > lapStart && shooting.Start <= lapEnd {
+				segments[i].Range += rangeTime(shooting)
+				segments[i].Penalty += shooting.Penalty
+			}
+		}
+		segments[i].Ski = lap.Time - segments[i].Range - segments[i].Penalty
+	}
+	return segments
+}
+
+func name(result model.Result) string {
+	if result.Competitor.Name != "" {
+		return result.Competitor.Name
+	}
+	return fmt.Sprintf("competitor(%d)", result.RunnerID)
+}
+
+// Write writes the report as text, deltas are signed
+func (r Report) Write(w io.Writer) {
+	t := r.Timing
+	fmt.Fprintf(w, "%s vs %s, deltas of %s\n", name(r.A), name(r.B), name(r.A))
+	for _, lap := range r.Laps {
+		fmt.Fprintf(w, "Lap %d: %s vs %s %s, total %s {ski %s, range %s, penalty %s}\n",
+			lap.Lap, t.Format(lap.A), t.Format(lap.B), t.FormatAdjustment(lap.Delta), t.FormatAdjustment(lap.Cumulative),
+			t.FormatAdjustment(lap.Segments.Ski), t.FormatAdjustment(lap.Segments.Range), t.FormatAdjustment(lap.Segments.Penalty))
+	}
+	for _, stage := range r.Stages {
+		fmt.Fprintf(w, "Shooting %d: %d/%d vs %d/%d, range %s vs %s %s, penalty %s vs %s %s\n",
+			stage.Stage, stage.A.Hits, stage.A.Shots, stage.B.Hits, stage.B.Shots,
+			t.Format(rangeTime(stage.A)), t.Format(rangeTime(stage.B)), t.FormatAdjustment(stage.RangeDelta),
+			t.Format(stage.A.Penalty), t.Format(stage.B.Penalty), t.FormatAdjustment(stage.PenaltyDelta))
+	}
+	fmt.Fprintf(w, "Total: ski %s, range %s, penalty %s, adjustments %s\n",
+		t.FormatAdjustment(r.Total.Ski), t.FormatAdjustment(r.Total.Range), t.FormatAdjustment(r.Total.Penalty),
+		t.FormatAdjustment(r.Adjustments))
+}
@@ -0,0 +1,86 @@
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	a := model.Result{
+		RunnerID:    1,
+		StartTime:   10000,
+		Adjustments: []model.Adjustment{{Delta: 5000, Reason: "false start"}},
+		Laps:        []model.LapResult{{Time: 300000}, {Time: 280000}},
+		Shootings: []model.Shooting{
+			{FiringRange: 1, Hits: 3, Shots: 5, Start: 200000, End: 240000, Penalty: 60000},
+			{FiringRange: 2, Hits: 5, Shots: 5, Start: 500000, End: 530000},
+		},
+	}
+	b := model.Result{
+		RunnerID:  2,
+		StartTime: 40000,
+		Laps:      []model.LapResult{{Time: 270000}, {Time: 290000}, {Time: 250000}},
+		Shootings: []model.Shooting{
+			{FiringRange: 1, Hits: 5, Shots: 5, Start: 230000, End: 265000},
+			{FiringRange: 1, Hits: 4, Shots: 5, Start: 520000, End: 550000, Penalty: 30000},
+		},
+	}
+
+	r := Compare(a, b, model.RawTiming)
+	expectedLaps := []Lap{
+		{
+			Lap: 1, A: 300000, B: 270000, Delta: 30000, Cumulative: 30000,
+			SegmentsA: Segments{Ski: 200000, Range: 40000, Penalty: 60000},
+			SegmentsB: Segments{Ski: 235000, Range: 35000},
+			Segments:  Segments{Ski: -35000, Range: 5000, Penalty: 60000},
+		},
+		{
+			Lap: 2, A: 280000, B: 290000, Delta: -10000, Cumulative: 20000,
+			SegmentsA: Segments{Ski: 250000, Range: 30000},
+			SegmentsB: Segments{Ski: 230000, Range: 30000, Penalty: 30000},
+			Segments:  Segments{Ski: 20000, Penalty: -30000},
+		},
+	}
+	if !reflect.DeepEqual(r.Laps, expectedLaps) {
+		t.Errorf("Expected laps %+v, got %+v", expectedLaps, r.Laps)
+	}
+	if expected := (Segments{Ski: -15000, Range: 5000, Penalty: 30000}); r.Total != expected {
+		t.Errorf("Expected total %+v, got %+v", expected, r.Total)
+	}
+	if r.Adjustments != 5000 {
+		t.Errorf("Expected adjustments delta 5000, got %d", r.Adjustments)
+	}
+	if len(r.Stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(r.Stages))
+	}
+	if s := r.Stages[0]; s.MissesDelta != 2 || s.RangeDelta != 5000 || s.PenaltyDelta != 60000 {
+		t.Errorf("Unexpected first stage %+v", s)
+	}
+	if s := r.Stages[1]; s.MissesDelta != -1 || s.RangeDelta != 0 || s.PenaltyDelta != -30000 {
+		t.Errorf("Unexpected second stage %+v", s)
+	}
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+	if !strings.Contains(buf.String(), "Lap 1: 00:05:00.000 vs 00:04:30.000 +00:00:30.000") {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestFind(t *testing.T) {
+	doc := classification.Document{Classifications: []classification.Classification{{
+		Name:      classification.OverallName,
+		Standings: []classification.Standing{{Rank: 1, Result: model.Result{RunnerID: 3}}},
+	}}}
+	if result, err := Find(doc, 3); err != nil || result.RunnerID != 3 {
+		t.Errorf("Expected competitor(3), got %+v, %v", result, err)
+	}
+	if _, err := Find(doc, 4); !errors.Is(err, errNoCompetitor) {
+		t.Errorf("Expected %v, got %v", errNoCompetitor, err)
+	}
+}
@@ -0,0 +1,149 @@
+// Package draw assigns start times to competitors
+package draw
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"sort"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errUnknownMode Err = "unknown draw mode"
+	errGroups      Err = "amount of start groups must be positive"
+	errNoOverall   Err = "ranking has no overall classification"
+)
+
+// Mode is a way of ordering competitors
+type Mode string
+
+// Modes
+const (
+	// ModeRandom draws all competitors randomly
+	ModeRandom Mode = "random"
+	// ModeRanking starts competitors in ranking order, unranked are drawn randomly after them
+	ModeRanking Mode = "ranking"
+	// ModeGroups splits competitors ordered by ranking into start groups drawn randomly
+	ModeGroups Mode = "groups"
+)
+
+// Options of the draw, times are in milliseconds
+type Options struct {
+	Mode     Mode
+	Seed     uint64
+	Start    int
+	Interval int
+	Groups   int
+	// Ranking is a rank of competitor ID from previous results
+	Ranking map[int]int
+}
+
+// Draw returns competitors in start order with start times set
+func Draw(competitors []model.Competitor, opts Options) ([]model.Competitor, error) {
+	rnd := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	drawn := make([]model.Competitor, len(competitors))
+	copy(drawn, competitors)
+
+	switch opts.Mode {
+	case ModeRandom:
+		shuffle(rnd, drawn)
+	case ModeRanking:
+		orderByRanking(rnd, drawn, opts.Ranking)
+	case ModeGroups:
+		if opts.Groups <= 0 {
+			return nil, errGroups
+		}
+		orderByRanking(rnd, drawn, opts.Ranking)
+		size := (len(drawn) + opts.Groups - 1) / opts.Groups
+		for start := 0; start < len(drawn); start += size {
+			shuffle(rnd, drawn[start:min(start+size, len(drawn))])
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownMode, opts.Mode)
+	}
+
+	for i := range drawn {
+		drawn[i].StartTime = model.RawTiming.Format(opts.Start + i*opts.Interval)
+	}
+	return drawn, nil
+}
+
+// Events returns incoming event lines setting drawn start times at the time
+func Events(drawn []model.Competitor, at int) ([]string, error) {
+	events := make([]string, 0, len(drawn))
+	for _, c := range drawn {
+		startTime, err := model.ParseTime(c.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("start time of competitor %d: %w", c.ID, err)
+		}
+		e := event.Event{Time: at, Type: event.SetStartTime, Competitor: c.ID, StartTime: startTime}
+		events = append(events, e.String())
+	}
+	return events, nil
+}
+
+// LoadRanking reads competitor ranks from overall classification of JSON resulting table
+func LoadRanking(path string) (map[int]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := classification.Document{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for _, c := range doc.Classifications {
+		if c.Name != classification.OverallName {
+			continue
+		}
+		ranking := map[int]int{}
+		for _, standing := range c.Standings {
+			if standing.Rank > 0 {
+				ranking[standing.Result.RunnerID] = standing.Rank
+			}
+		}
+		return ranking, nil
+	}
+	return nil, errNoOverall
+}
+
+// orderByRanking puts ranked competitors first by rank and bib, unranked are shuffled after them
+func orderByRanking(rnd *rand.Rand, competitors []model.Competitor, ranking map[int]int) {
+	sort.SliceStable(competitors, func(i, j int) bool {
+		ri, iRanked := ranking[competitors[i].ID]
+		rj, jRanked := ranking[competitors[j].ID]
+		if iRanked != jRanked {
+			return iRanked
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return competitors[i].Bib < competitors[j].Bib
+	})
+	ranked := 0
+	for ranked < len(competitors) {
+		if _, ok := ranking[competitors[ranked].ID]; !ok {
+			break
+		}
+		ranked++
+	}
+	shuffle(rnd, competitors[ranked:])
+}
+
+func shuffle(rnd *rand.Rand, competitors []model.Competitor) {
+	rnd.Shuffle(len(competitors), func(i, j int) {
+		competitors[i], competitors[j] = competitors[j], competitors[i]
+	})
+}
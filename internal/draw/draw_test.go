@@ -0,0 +1,106 @@
+package draw
+
+import (
+	"errors"
+	"racingMetrics/internal/model"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestDrawStartTimes(t *testing.T) {
+	drawn, err := Draw(competitors(3), Options{Mode: ModeRandom, Seed: 1, Start: 36000000, Interval: 30000})
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+
+	want := []string{"10:00:00.000", "10:00:30.000", "10:01:00.000"}
+	for i, c := range drawn {
+		if c.StartTime != want[i] {
+			t.Errorf("Expected start time %s, got %s", want[i], c.StartTime)
+		}
+	}
+	if ids := sortedIDs(drawn); !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf("Expected all competitors drawn, got %v", ids)
+	}
+
+	events, err := Events(drawn[:1], 34200000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[09:30:00.000] 2 " + strconv.Itoa(drawn[0].ID) + " 10:00:00.000"; events[0] != want {
+		t.Errorf("Expected event %q, got %q", want, events[0])
+	}
+}
+
+func TestDrawIsReproducible(t *testing.T) {
+	opts := Options{Mode: ModeRandom, Seed: 42, Interval: 1000}
+	first, err := Draw(competitors(20), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Draw(competitors(20), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same draw for the same seed")
+	}
+}
+
+func TestDrawRanking(t *testing.T) {
+	ranking := map[int]int{5: 1, 3: 2, 4: 2}
+	drawn, err := Draw(competitors(6), Options{Mode: ModeRanking, Seed: 1, Ranking: ranking})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := []int{drawn[0].ID, drawn[1].ID, drawn[2].ID}; !reflect.DeepEqual(ids, []int{5, 3, 4}) {
+		t.Errorf("Expected ranked competitors 5, 3, 4 first, got %v", ids)
+	}
+	if ids := sortedIDs(drawn[3:]); !reflect.DeepEqual(ids, []int{1, 2, 6}) {
+		t.Errorf("Expected unranked competitors after ranked, got %v", ids)
+	}
+}
+
+func TestDrawGroups(t *testing.T) {
+	ranking := map[int]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6}
+	drawn, err := Draw(competitors(7), Options{Mode: ModeGroups, Seed: 1, Groups: 3, Ranking: ranking})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	for i, group := range groups {
+		if ids := sortedIDs(drawn[i*3 : min(i*3+3, len(drawn))]); !reflect.DeepEqual(ids, group) {
+			t.Errorf("Expected group %v, got %v", group, ids)
+		}
+	}
+}
+
+func TestDrawInvalidOptions(t *testing.T) {
+	if _, err := Draw(competitors(1), Options{Mode: "alphabet"}); !errors.Is(err, errUnknownMode) {
+		t.Errorf("Expected %v, got %v", errUnknownMode, err)
+	}
+	if _, err := Draw(competitors(1), Options{Mode: ModeGroups}); !errors.Is(err, errGroups) {
+		t.Errorf("Expected %v, got %v", errGroups, err)
+	}
+}
+
+func competitors(n int) []model.Competitor {
+	competitors := make([]model.Competitor, 0, n)
+	for id := 1; id <= n; id++ {
+		competitors = append(competitors, model.Competitor{ID: id, Bib: 100 + id})
+	}
+	return competitors
+}
+
+func sortedIDs(competitors []model.Competitor) []int {
+	ids := []int{}
+	for _, c := range competitors {
+		ids = append(ids, c.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,173 @@
+package event
+
+import (
+	"fmt"
+	"racingMetrics/internal/model"
+	"strconv"
+	"strings"
+)
+
+// Type of incoming event
+type Type int
+
+// Incoming event types
+const (
+	Register Type = iota + 1
+	SetStartTime
+	OnStartLine
+	Start
+	OnFiringRange
+	HitTarget
+	LeftFiringRange
+	EnterPenalty
+	LeftPenalty
+	EndMainLap
+	CantContinue
+	AmendTime
+	AmendParam
+	Void
+	AdjustTime
+	Disqualify
+	Lapped
+)
+
+// IsCorrection reports whether the event corrects another accepted event
+func (t Type) IsCorrection() bool {
+	return t == AmendTime || t == AmendParam || t == Void
+}
+
+// Event is an incoming event, times are in milliseconds since midnight.
+// Only parameters of the event type are set
+type Event struct {
+	// Seq is a 1-based number of the event in the events file
+	Seq        int  `json:"seq"`
+	Time       int  `json:"time"`
+	Type       Type `json:"type"`
+	Competitor int  `json:"competitor"`
+
+	// StartTime is a draw start time of SetStartTime
+	StartTime int `json:"startTime,omitempty"`
+	// FiringRange of OnFiringRange
+	FiringRange int `json:"firingRange,omitempty"`
+	// Target of HitTarget
+	Target int `json:"target,omitempty"`
+	// Delta is a penalty or, if negative, a bonus of AdjustTime
+	Delta int `json:"delta,omitempty"`
+	// Reason of CantContinue, AdjustTime and Disqualify
+	Reason string `json:"reason,omitempty"`
+	// RefSeq is Seq of the event corrected by AmendTime, AmendParam or Void
+	RefSeq int `json:"refSeq,omitempty"`
+	// NewTime of the event corrected by AmendTime
+	NewTime int `json:"newTime,omitempty"`
+	// NewParams of the event corrected by AmendParam
+	NewParams []string `json:"newParams,omitempty"`
+}
+
+// paramNames are names of event parameters in JSON Lines records,
+// all of them are required
+var paramNames = map[Type][]string{
+	SetStartTime:  {"startTime"},
+	OnFiringRange: {"firingRange"},
+	HitTarget:     {"target"},
+	CantContinue:  {"comment"},
+	AmendTime:     {"seq", "time"},
+	AmendParam:    {"seq", "value"},
+	Void:          {"seq"},
+	AdjustTime:    {"delta", "reason"},
+	Disqualify:    {"reason"},
+}
+
+// requiredParams returns a number of parameters required by the event type
+func requiredParams(t Type) int {
+	return len(paramNames[t])
+}
+
+// Event returns typed event with sequence number seq
+func (l Line) Event(seq int) (Event, error) {
+	time, err := model.ParseTime(l.Time)
+	if err != nil {
+		return Event{}, fmt.Errorf("%w: %q", errTime, l.Time)
+	}
+	e := Event{
+		Seq:        seq,
+		Time:       time,
+		Type:       Type(l.EventID),
+		Competitor: l.RunnerID,
+	}
+	return e, e.SetParams(l.Params)
+}
+
+// SetParams sets typed parameters of the event from their text form
+func (e *Event) SetParams(params []string) error {
+	if required := requiredParams(e.Type); len(params) < required {
+		return fmt.Errorf("%w: event %d requires %d", errParams, e.Type, required)
+	}
+
+	var err error
+	switch e.Type {
+	case SetStartTime:
+		e.StartTime, err = model.ParseTime(params[0])
+	case OnFiringRange:
+		e.FiringRange, err = strconv.Atoi(params[0])
+	case HitTarget:
+		e.Target, err = strconv.Atoi(params[0])
+	case CantContinue:
+		e.Reason = strings.Join(params, " ")
+	case AmendTime:
+		if e.RefSeq, err = strconv.Atoi(params[0]); err == nil {
+			e.NewTime, err = model.ParseTime(params[1])
+		}
+	case AmendParam:
+		e.RefSeq, err = strconv.Atoi(params[0])
+		e.NewParams = params[1:]
+	case Void:
+		e.RefSeq, err = strconv.Atoi(params[0])
+	case AdjustTime:
+		e.Delta, err = model.ParseAdjustment(params[0])
+		e.Reason = params[1]
+	case Disqualify:
+		e.Reason = params[0]
+	}
+	if err != nil {
+		return fmt.Errorf("%w of event %d: %w", errInvalidParam, e.Type, err)
+	}
+	return nil
+}
+
+// Params returns text form of typed parameters of the event
+func (e Event) Params() []string {
+	switch e.Type {
+	case SetStartTime:
+		return []string{model.RawTiming.Format(e.StartTime)}
+	case OnFiringRange:
+		return []string{strconv.Itoa(e.FiringRange)}
+	case HitTarget:
+		return []string{strconv.Itoa(e.Target)}
+	case CantContinue, Disqualify:
+		return []string{e.Reason}
+	case AmendTime:
+		return []string{strconv.Itoa(e.RefSeq), model.RawTiming.Format(e.NewTime)}
+	case AmendParam:
+		return append([]string{strconv.Itoa(e.RefSeq)}, e.NewParams...)
+	case Void:
+		return []string{strconv.Itoa(e.RefSeq)}
+	case AdjustTime:
+		return []string{model.RawTiming.FormatAdjustment(e.Delta), e.Reason}
+	}
+	return nil
+}
+
+// Line returns text line of the event
+func (e Event) Line() Line {
+	return Line{
+		Time:     model.RawTiming.Format(e.Time),
+		EventID:  int(e.Type),
+		RunnerID: e.Competitor,
+		Params:   e.Params(),
+	}
+}
+
+// String formats the event as an events file line
+func (e Event) String() string {
+	return e.Line().String()
+}
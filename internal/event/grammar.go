@@ -0,0 +1,157 @@
+package event
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Event file grammar:
+//
+//	file    = [header] { line }
+//	header  = "#!events" version
+//	line    = [event] [comment]
+//	event   = "[" time "]" eventID competitorID { param }
+//	param   = word | '"' { char | '\"' | '\\' } '"'
+//	comment = "#" { char }
+//
+// Fields are separated by whitespace, lines without an event are skipped.
+// A quoted parameter may contain spaces and '#', a word may not start with '"' or '#'.
+
+// Version is the event file version written by this package
+const Version = "v1"
+
+const headerPrefix = "#!events"
+
+// Reader reads events of an event file skipping blank lines and comments
+type Reader struct {
+	scanner *bufio.Scanner
+	lineNum int
+	seq     int
+}
+
+// NewReader returns Reader of r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r)}
+}
+
+// Read returns the next event or io.EOF at the end of the file
+func (r *Reader) Read() (Event, error) {
+	for r.scanner.Scan() {
+		r.lineNum++
+		text := r.scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(text), headerPrefix) {
+			if err := r.header(text); err != nil {
+				return Event{}, fmt.Errorf("line %d: %w", r.lineNum, err)
+			}
+			continue
+		}
+		fields, err := split(text)
+		if err != nil {
+			return Event{}, fmt.Errorf("line %d: %w", r.lineNum, err)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		l, err := parseFields(fields)
+		if err != nil {
+			return Event{}, fmt.Errorf("line %d: %w", r.lineNum, err)
+		}
+		r.seq++
+		return l.Event(r.seq)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// LineNum returns number of the last read line of the file
+func (r *Reader) LineNum() int {
+	return r.lineNum
+}
+
+func (r *Reader) header(text string) error {
+	if r.lineNum != 1 {
+		return errHeader
+	}
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), headerPrefix))
+	if version != Version {
+		return fmt.Errorf("%w: %q", errVersion, version)
+	}
+	return nil
+}
+
+// split returns whitespace separated fields of the line up to a comment,
+// unquoting quoted fields
+func split(line string) ([]string, error) {
+	var fields []string
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '#':
+			return fields, nil
+		case r == '"':
+			field, n, err := unquote(line[i:])
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+			i += n
+		default:
+			end := strings.IndexFunc(line[i:], unicode.IsSpace)
+			if end < 0 {
+				end = len(line) - i
+			}
+			fields = append(fields, line[i:i+end])
+			i += end
+		}
+	}
+	return fields, nil
+}
+
+// unquote returns the quoted field at the start of s and its length in s
+func unquote(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			next, _ := utf8.DecodeRuneInString(s[i+1:])
+			if i+1 < len(s) && !unicode.IsSpace(next) {
+				return "", 0, errQuote
+			}
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, errQuote
+}
+
+// quote returns the parameter as is or quoted if it can't be read back as a word
+func quote(param string) string {
+	if param != "" && !strings.ContainsFunc(param, unicode.IsSpace) &&
+		param[0] != '"' && param[0] != '#' {
+		return param
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(param); i++ {
+		if param[i] == '"' || param[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(param[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
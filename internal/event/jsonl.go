@@ -0,0 +1,185 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Format is an event file format
+type Format string
+
+// Formats
+const (
+	// FormatAuto detects JSON Lines by '{' at the start of the first record
+	FormatAuto  Format = ""
+	FormatText  Format = "text"
+	FormatJSONL Format = "jsonl"
+)
+
+const (
+	errUnknownFormat Err = "unknown event file format"
+	errUnknownParam  Err = "unknown event parameter"
+	errParamValue    Err = "event parameter must be a string or a number, the last one may be an array of them"
+	errMissingField  Err = "missing event field"
+)
+
+// Valid reports whether the format is known
+func (f Format) Valid() bool {
+	return f == FormatAuto || f == FormatText || f == FormatJSONL
+}
+
+// peekSize is how far the first record is looked for when the format is detected
+const peekSize = 4096
+
+// Decoder reads incoming events
+type Decoder interface {
+	// Read returns the next event or io.EOF at the end of the file
+	Read() (Event, error)
+}
+
+// NewDecoder returns Decoder of the event file format
+func NewDecoder(r io.Reader, format Format) (Decoder, error) {
+	br := bufio.NewReader(r)
+	if format == FormatAuto {
+		format = detect(br)
+	}
+	switch format {
+	case FormatText:
+		return NewReader(br), nil
+	case FormatJSONL:
+		return NewJSONLReader(br), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+}
+
+func detect(br *bufio.Reader) Format {
+	head, _ := br.Peek(peekSize)
+	head = bytes.TrimLeft(head, " \t\r\n")
+	if len(head) > 0 && head[0] == '{' {
+		return FormatJSONL
+	}
+	return FormatText
+}
+
+// record is a JSON Lines event, time, event and competitor are required
+//
+//	{"time": "09:05:59.867", "event": 5, "competitor": 1, "params": {"firingRange": 1}}
+type record struct {
+	Time       *string                    `json:"time"`
+	Event      *int                       `json:"event"`
+	Competitor *int                       `json:"competitor"`
+	Params     map[string]json.RawMessage `json:"params,omitempty"`
+}
+
+// JSONLReader reads events of a JSON Lines file skipping blank lines
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	lineNum int
+	seq     int
+}
+
+// NewJSONLReader returns JSONLReader of r
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{scanner: bufio.NewScanner(r)}
+}
+
+// Read returns the next event or io.EOF at the end of the file
+func (r *JSONLReader) Read() (Event, error) {
+	for r.scanner.Scan() {
+		r.lineNum++
+		text := bytes.TrimSpace(r.scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		e, err := decodeRecord(text, r.seq+1)
+		if err != nil {
+			return Event{}, fmt.Errorf("line %d: %w", r.lineNum, err)
+		}
+		r.seq++
+		return e, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// LineNum returns number of the last read line of the file
+func (r *JSONLReader) LineNum() int {
+	return r.lineNum
+}
+
+func decodeRecord(data []byte, seq int) (Event, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	rec := record{}
+	if err := decoder.Decode(&rec); err != nil {
+		return Event{}, err
+	}
+	if decoder.More() {
+		return Event{}, errors.New("one event per line expected")
+	}
+
+	switch {
+	case rec.Time == nil:
+		return Event{}, fmt.Errorf("%w: time", errMissingField)
+	case rec.Event == nil:
+		return Event{}, fmt.Errorf("%w: event", errMissingField)
+	case rec.Competitor == nil:
+		return Event{}, fmt.Errorf("%w: competitor", errMissingField)
+	}
+
+	l := Line{
+		Time:     *rec.Time,
+		EventID:  *rec.Event,
+		RunnerID: *rec.Competitor,
+	}
+	names := paramNames[Type(l.EventID)]
+	for name := range rec.Params {
+		if !slices.Contains(names, name) {
+			return Event{}, fmt.Errorf("%w of event %d: %q", errUnknownParam, l.EventID, name)
+		}
+	}
+	for i, name := range names {
+		raw, ok := rec.Params[name]
+		if !ok {
+			break
+		}
+		values, err := paramValues(raw, i == len(names)-1)
+		if err != nil {
+			return Event{}, fmt.Errorf("%s: %w", name, err)
+		}
+		l.Params = append(l.Params, values...)
+	}
+	return l.Event(seq)
+}
+
+// paramValues returns text parameters of a string, a number
+// or, for the last parameter of the event, an array of them
+func paramValues(raw json.RawMessage, last bool) ([]string, error) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || !last {
+		values = []json.RawMessage{raw}
+	}
+	params := make([]string, 0, len(values))
+	for _, value := range values {
+		var str string
+		if err := json.Unmarshal(value, &str); err == nil {
+			params = append(params, str)
+			continue
+		}
+		var number json.Number
+		if err := json.Unmarshal(value, &number); err == nil {
+			params = append(params, number.String())
+			continue
+		}
+		return nil, errParamValue
+	}
+	return params, nil
+}
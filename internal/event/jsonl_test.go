@@ -0,0 +1,91 @@
+package event
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, d Decoder) []Event {
+	t.Helper()
+	var events []Event
+	for {
+		e, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		events = append(events, e)
+	}
+}
+
+func TestDecodersShareLines(t *testing.T) {
+	text := `[09:05:59.867] 1 1
+[09:15:00.841] 2 1 09:30:00.000
+[09:49:33.123] 5 1 1
+[09:59:45.000] 11 1 "Lost in the forest"
+[10:40:00.000] 15 1 +00:02:00 EQUIPMENT
+[10:40:10.000] 13 1 5 "Broken ski" again
+`
+	jsonl := `{"time": "09:05:59.867", "event": 1, "competitor": 1}
+{"time": "09:15:00.841", "event": 2, "competitor": 1, "params": {"startTime": "09:30:00.000"}}
+
+{"time": "09:49:33.123", "event": 5, "competitor": 1, "params": {"firingRange": 1}}
+{"time": "09:59:45.000", "event": 11, "competitor": 1, "params": {"comment": "Lost in the forest"}}
+{"time": "10:40:00.000", "event": 15, "competitor": 1, "params": {"reason": "EQUIPMENT", "delta": "+00:02:00"}}
+{"time": "10:40:10.000", "event": 13, "competitor": 1, "params": {"seq": 5, "value": ["Broken ski", "again"]}}
+`
+	textDecoder, err := NewDecoder(strings.NewReader(text), FormatAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonlDecoder, err := NewDecoder(strings.NewReader(jsonl), FormatAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := jsonlDecoder.(*JSONLReader); !ok {
+		t.Fatalf("Expected JSON Lines to be detected, got %T", jsonlDecoder)
+	}
+
+	want := readAll(t, textDecoder)
+	if got := readAll(t, jsonlDecoder); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestJSONLErrors(t *testing.T) {
+	tests := map[string]error{
+		`{"time": "09:05:59", "event": 1, "competitor": 1}`:                                   errTime,
+		`{"time": "09:05:59.867", "event": 2, "competitor": 1}`:                               errParams,
+		`{"time": "09:05:59.867", "event": 1, "competitor": 1, "params": {"target": 1}}`:      errUnknownParam,
+		`{"time": "09:05:59.867", "event": 6, "competitor": 1, "params": {"target": true}}`:   errParamValue,
+		`{"time": "09:05:59.867", "event": 12, "competitor": 1, "params": {"seq": [1, 2]}}`:   errParamValue,
+		`{"time": "09:05:59.867", "event": 6, "competitor": 1, "params": {"target": "abc"}}`:  errInvalidParam,
+		`{"time": "09:05:59.867", "event": 12, "competitor": 1, "params": {"time": "10:00"}}`: errParams,
+		`{"time": "09:05:59.867", "competitor": 1}`:                                           errMissingField,
+		`{"time": "09:05:59.867", "event": 1}`:                                                errMissingField,
+		`{"event": 1, "competitor": 1}`:                                                       errMissingField,
+	}
+	for record, want := range tests {
+		_, err := NewJSONLReader(strings.NewReader(record)).Read()
+		if !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", record, want, err)
+		}
+	}
+
+	_, err := NewJSONLReader(strings.NewReader("\n" + `{"time": "09:05:59.867", "event": 1}`)).Read()
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Errorf("Expected error at line 2, got %v", err)
+	}
+	_, err = NewJSONLReader(strings.NewReader(`{"time": "09:05:59.867", "event": 1, "runner": 1}`)).Read()
+	if err == nil {
+		t.Errorf("Expected unknown field error")
+	}
+	if _, err := NewDecoder(strings.NewReader(""), "xml"); !errors.Is(err, errUnknownFormat) {
+		t.Errorf("Expected %v, got %v", errUnknownFormat, err)
+	}
+}
@@ -0,0 +1,104 @@
+// Package event parses and formats incoming event lines
+package event
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errEmptyLine    Err = "empty event line"
+	errNoFields     Err = "event line must have time, event ID and competitor ID"
+	errTime         Err = "event time must be [hh:mm:ss.mmm]"
+	errEventID      Err = "invalid event ID"
+	errRunnerID     Err = "invalid competitor ID"
+	errParams       Err = "not enough event parameters"
+	errInvalidParam Err = "invalid event parameter"
+	errQuote        Err = "malformed quoted parameter"
+	errVersion      Err = "unsupported event file version"
+	errHeader       Err = "version header must be the first line"
+)
+
+// Line is an incoming event line "[hh:mm:ss.mmm] eventID competitorID [params...]"
+type Line struct {
+	Time     string   `json:"time"`
+	EventID  int      `json:"eventID"`
+	RunnerID int      `json:"runnerID"`
+	Params   []string `json:"params,omitempty"`
+}
+
+// Parse returns event line checking its fields and number of parameters
+func Parse(line string) (Line, error) {
+	fields, err := split(line)
+	if err != nil {
+		return Line{}, err
+	}
+	return parseFields(fields)
+}
+
+func parseFields(fields []string) (Line, error) {
+	if len(fields) == 0 {
+		return Line{}, errEmptyLine
+	}
+	if len(fields) < 3 {
+		return Line{}, errNoFields
+	}
+
+	timeField := fields[0]
+	if len(timeField) < 2 || timeField[0] != '[' || timeField[len(timeField)-1] != ']' {
+		return Line{}, fmt.Errorf("%w: %q", errTime, timeField)
+	}
+	eventID, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Line{}, fmt.Errorf("%w: %q", errEventID, fields[1])
+	}
+	runnerID, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return Line{}, fmt.Errorf("%w: %q", errRunnerID, fields[2])
+	}
+
+	l := Line{
+		Time:     timeField[1 : len(timeField)-1],
+		EventID:  eventID,
+		RunnerID: runnerID,
+	}
+	if len(fields) > 3 {
+		l.Params = fields[3:]
+	}
+	return l, l.Validate()
+}
+
+// Validate checks event time and parameters required by the event
+func (l Line) Validate() error {
+	_, err := l.Event(0)
+	return err
+}
+
+// ParseEvent returns typed event of the line with sequence number seq
+func ParseEvent(line string, seq int) (Event, error) {
+	l, err := Parse(line)
+	if err != nil {
+		return Event{}, err
+	}
+	return l.Event(seq)
+}
+
+// String formats the line so that Parse returns it back
+func (l Line) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %d %d", l.Time, l.EventID, l.RunnerID)
+	for _, param := range l.Params {
+		b.WriteByte(' ')
+		b.WriteString(quote(param))
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package event
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse("[09:05:59.867] 11 1 Lost in the forest")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := Line{Time: "09:05:59.867", EventID: 11, RunnerID: 1, Params: []string{"Lost", "in", "the", "forest"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseQuoted(t *testing.T) {
+	got, err := Parse(`[09:05:59.867] 11 1 "Broken ski, #2 \"left\"" # spare skis`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if want := []string{`Broken ski, #2 "left"`}; !reflect.DeepEqual(got.Params, want) {
+		t.Errorf("Expected params %q, got %q", want, got.Params)
+	}
+	if want := `[09:05:59.867] 11 1 "Broken ski, #2 \"left\""`; got.String() != want {
+		t.Errorf("Expected line %s, got %s", want, got.String())
+	}
+}
+
+func TestReader(t *testing.T) {
+	file := "#!events v1\n# comment\n\n[09:05:59.867] 1 1\n   \n[09:15:00.841] 2 1 09:30:00.000 # draw\n"
+	events := readAll(t, NewReader(strings.NewReader(file)))
+	want := []Event{
+		{Seq: 1, Time: 32759867, Type: Register, Competitor: 1},
+		{Seq: 2, Time: 33300841, Type: SetStartTime, Competitor: 1, StartTime: 34200000},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Expected %+v, got %+v", want, events)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := map[string]error{
+		"#!events v2\n":                        errVersion,
+		"[09:05:59.867] 1 1\n#!events v1\n":    errHeader,
+		"[09:05:59.867] 1 1\n[09:05:59] 1 2\n": errTime,
+	}
+	for file, want := range tests {
+		reader := NewReader(strings.NewReader(file))
+		var err error
+		for err == nil {
+			_, err = reader.Read()
+		}
+		if !errors.Is(err, want) {
+			t.Errorf("%q: expected %v, got %v", file, want, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]error{
+		"":                         errEmptyLine,
+		"   ":                      errEmptyLine,
+		"[09:05:59.867] 1":         errNoFields,
+		"09:05:59.867 1 1":         errTime,
+		"[]  1 1":                  errTime,
+		"[":                        errNoFields,
+		"[ 1 1":                    errTime,
+		"[09:05:59] 1 1":           errTime,
+		"[25:05:59.867] 1 1":       errTime,
+		"[09:05:59.867] one 1":     errEventID,
+		"[09:05:59.867] 1 first":   errRunnerID,
+		"[09:05:59.867] 1 1.5 10":  errRunnerID,
+		`[09:05:59.867] 11 1 "a`:   errQuote,
+		`[09:05:59.867] 11 1 "a"b`: errQuote,
+		"# comment":                errEmptyLine,
+	}
+	for line, want := range tests {
+		if _, err := Parse(line); !errors.Is(err, want) {
+			t.Errorf("%q: expected %v, got %v", line, want, err)
+		}
+	}
+}
+
+func TestParseEventErrors(t *testing.T) {
+	tests := map[string]error{
+		"[09:00:01.000] 2 1":             errParams,
+		"[09:00:01.000] 2 1 10:00":       errInvalidParam,
+		"[09:00:01.000] 5 1 abc":         errInvalidParam,
+		"[09:00:01.000] 15 1 00:02:00 X": errInvalidParam,
+	}
+	for line, want := range tests {
+		if _, err := ParseEvent(line, 1); !errors.Is(err, want) {
+			t.Errorf("%q: expected %v, got %v", line, want, err)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("[09:05:59.867] 1 1")
+	f.Add("[09:15:00.841] 2 1 09:30:00.000")
+	f.Add("[09:59:45.000] 11 1 Lost in the forest")
+	f.Add("[10:40:00.000] 15 2 +00:02:00 EQUIPMENT")
+	f.Add(`[09:59:45.000] 11 1 "Broken ski, #2 \"left\"" # comment`)
+	f.Add(`[09:59:45.000] 11 1 ""`)
+	f.Add("")
+	f.Add("[")
+	f.Add("[] 1")
+	f.Fuzz(func(t *testing.T, line string) {
+		l, err := Parse(line)
+		if err != nil {
+			return
+		}
+		again, err := Parse(l.String())
+		if err != nil {
+			t.Fatalf("Formatted line %q doesn't parse: %v", l.String(), err)
+		}
+		if !reflect.DeepEqual(l, again) {
+			t.Fatalf("Round trip of %q: %+v != %+v", line, l, again)
+		}
+	})
+}
+
+func FuzzParseEvent(f *testing.F) {
+	f.Add("[09:15:00.841] 2 1 09:30:00.000")
+	f.Add("[09:49:33.123] 5 1 1")
+	f.Add("[09:59:45.000] 11 1 Lost in the forest")
+	f.Add("[10:40:00.000] 12 1 5 10:25:20.000")
+	f.Add(`[10:40:00.000] 13 1 5 "Broken ski" again`)
+	f.Add("[10:40:00.000] 15 2 -00:00:10.500 START")
+	f.Fuzz(func(t *testing.T, line string) {
+		e, err := ParseEvent(line, 1)
+		if err != nil {
+			return
+		}
+		again, err := ParseEvent(e.String(), 1)
+		if err != nil {
+			t.Fatalf("Formatted event %q doesn't parse: %v", e.String(), err)
+		}
+		if !reflect.DeepEqual(e, again) {
+			t.Fatalf("Round trip of %q: %+v != %+v", line, e, again)
+		}
+	})
+}
+
+func BenchmarkParseEvent(b *testing.B) {
+	lines := []string{
+		"[09:05:59.867] 1 1",
+		"[09:15:00.841] 2 1 09:30:00.000",
+		"[09:49:33.123] 5 1 1",
+		"[09:49:35.937] 6 1 2",
+		`[09:59:03.872] 11 1 "Lost in the forest"`,
+		"[10:40:00.000] 15 2 +00:02:00 EQUIPMENT",
+	}
+	b.ReportAllocs()
+	seq := 0
+	for b.Loop() {
+		seq++
+		if _, err := ParseEvent(lines[seq%len(lines)], seq); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package history builds performance history of athletes over an archive of processed races
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/season"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resultsName is a JSON resulting table of a race, as written by the batch command with -format json
+const resultsName = "table.json"
+
+// csvHeader is a header row of written CSV history
+var csvHeader = []string{"athlete", "race", "status", "rank", "lapSpeed", "accuracy", "rangeTime", "penaltySpeed", "stages"}
+
+// Race is a processed race of the archive
+type Race struct {
+	Name     string
+	Document classification.Document
+}
+
+// Stage is a shooting stage, RangeTime is time on the firing range in milliseconds
+type Stage struct {
+	Hits      int `json:"hits"`
+	Shots     int `json:"shots"`
+	RangeTime int `json:"rangeTime"`
+}
+
+// Entry is a race of the athlete, speeds are in m/s and times are in milliseconds.
+// LapSpeed is the mean of lap speeds, Accuracy is a share of hit targets
+type Entry struct {
+	Race         string       `json:"race"`
+	Status       model.Status `json:"status"`
+	Rank         int          `json:"rank,omitempty"`
+	LapSpeeds    []float64    `json:"lapSpeeds"`
+	LapSpeed     float64      `json:"lapSpeed"`
+	Stages       []Stage      `json:"stages"`
+	Accuracy     float64      `json:"accuracy"`
+	RangeTime    int          `json:"rangeTime"`
+	PenaltySpeed float64      `json:"penaltySpeed"`
+}
+
+// Trend is a least squares slope of the metrics per race,
+// races without the metric (no laps, shooting or penalty loops) are skipped
+type Trend struct {
+	LapSpeed     float64 `json:"lapSpeed"`
+	Accuracy     float64 `json:"accuracy"`
+	RangeTime    float64 `json:"rangeTime"`
+	PenaltySpeed float64 `json:"penaltySpeed"`
+}
+
+// History of an athlete ordered by race.
+// StageAccuracy is accuracy of the first, second and further shootings over all races
+type History struct {
+	Athlete       season.Athlete `json:"athlete"`
+	Races         []Entry        `json:"races"`
+	StageAccuracy []float64      `json:"stageAccuracy"`
+	Trend         Trend          `json:"trend"`
+}
+
+// Load reads resulting tables of archive directories ordered by name,
+// so names are expected to sort races chronologically, like 2025-12-01-ostersund
+func Load(root string) ([]Race, error) {
+	var races []Race
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != resultsName {
+			return err
+		}
+		name, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc := classification.Document{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("race %s: %w", name, err)
+		}
+		races = append(races, Race{Name: name, Document: doc})
+		return nil
+	})
+	sort.Slice(races, func(i, j int) bool {
+		return races[i].Name < races[j].Name
+	})
+	return races, err
+}
+
+// Build returns histories of all athletes of the races ordered by athlete
+func Build(races []Race) []History {
+	histories := map[season.Athlete]*History{}
+	for _, race := range races {
+		for _, c := range race.Document.Classifications {
+			if c.Name != classification.OverallName {
+				continue
+			}
+			for _, standing := range c.Standings {
+				athlete := season.AthleteOf(standing)
+				if histories[athlete] == nil {
+					histories[athlete] = &History{Athlete: athlete}
+				}
+				h := histories[athlete]
+				h.Races = append(h.Races, entry(race.Name, standing))
+			}
+		}
+	}
+
+	result := make([]History, 0, len(histories))
+	for _, h := range histories {
+		h.StageAccuracy = stageAccuracy(h.Races)
+		h.Trend = trend(h.Races)
+		result = append(result, *h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Athlete.String() < result[j].Athlete.String()
+	})
+	return result
+}
+
+func entry(race string, standing classification.Standing) Entry {
+	result := standing.Result
+	e := Entry{
+		Race:         race,
+		Status:       result.Status,
+		Rank:         standing.Rank,
+		LapSpeeds:    make([]float64, 0, len(result.Laps)),
+		Stages:       make([]Stage, 0, len(result.Shootings)),
+		PenaltySpeed: result.PenaltySpeed,
+	}
+	for _, lap := range result.Laps {
+		e.LapSpeeds = append(e.LapSpeeds, lap.Speed)
+		e.LapSpeed += lap.Speed / float64(len(result.Laps))
+	}
+	hits, shots := 0, 0
+	for _, shooting := range result.Shootings {
+		stage := Stage{Hits: shooting.Hits, Shots: shooting.Shots}
+		if shooting.End > 0 {
+			stage.RangeTime = shooting.End - shooting.Start
+		}
+		e.Stages = append(e.Stages, stage)
+		e.RangeTime += stage.RangeTime
+		hits += shooting.Hits
+		shots += shooting.Shots
+	}
+	if shots > 0 {
+		e.Accuracy = float64(hits) / float64(shots)
+	}
+	return e
+}
+
+func stageAccuracy(entries []Entry) []float64 {
+	var hits, shots []int
+	for _, e := range entries {
+		for i, stage := range e.Stages {
+			if i == len(shots) {
+				hits, shots = append(hits, 0), append(shots, 0)
+			}
+			hits[i] += stage.Hits
+			shots[i] += stage.Shots
+		}
+	}
+	accuracy := make([]float64, len(shots))
+	for i := range shots {
+		if shots[i] > 0 {
+			accuracy[i] = float64(hits[i]) / float64(shots[i])
+		}
+	}
+	return accuracy
+}
+
+func trend(entries []Entry) Trend {
+	var lapSpeed, accuracy, rangeTime, penaltySpeed []float64
+	for _, e := range entries {
+		if len(e.LapSpeeds) > 0 {
+			lapSpeed = append(lapSpeed, e.LapSpeed)
+		}
+		if len(e.Stages) > 0 {
+			accuracy = append(accuracy, e.Accuracy)
+			rangeTime = append(rangeTime, float64(e.RangeTime))
+		}
+		if e.PenaltySpeed > 0 {
+			penaltySpeed = append(penaltySpeed, e.PenaltySpeed)
+		}
+	}
+	return Trend{
+		LapSpeed:     slope(lapSpeed),
+		Accuracy:     slope(accuracy),
+		RangeTime:    slope(rangeTime),
+		PenaltySpeed: slope(penaltySpeed),
+	}
+}
+
+// slope returns least squares slope of values by their index, 0 for less than two values
+func slope(values []float64) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
+// WriteCSV writes a row per athlete and race with a header row,
+// stages are hits/shots of every shooting separated by spaces
+func WriteCSV(w io.Writer, histories []History) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, h := range histories {
+		for _, e := range h.Races {
+			stages := make([]string, len(e.Stages))
+			for i, stage := range e.Stages {
+				stages[i] = fmt.Sprintf("%d/%d", stage.Hits, stage.Shots)
+			}
+			rank := ""
+			if e.Rank > 0 {
+				rank = strconv.Itoa(e.Rank)
+			}
+			err := writer.Write([]string{
+				h.Athlete.String(),
+				e.Race,
+				string(e.Status),
+				rank,
+				strconv.FormatFloat(e.LapSpeed, 'f', 3, 64),
+				strconv.FormatFloat(e.Accuracy, 'f', 3, 64),
+				model.RawTiming.Format(e.RangeTime),
+				strconv.FormatFloat(e.PenaltySpeed, 'f', 3, 64),
+				strings.Join(stages, " "),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
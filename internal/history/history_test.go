@@ -0,0 +1,106 @@
+package history
+
+import (
+	"bytes"
+	"math"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/season"
+	"testing"
+)
+
+// testRace returns a race of a single athlete
+func testRace(name string, lapSpeeds []float64, shootings []model.Shooting, penaltySpeed float64) Race {
+	result := model.Result{
+		RunnerID:     1,
+		Competitor:   model.Competitor{Name: "Anna Ivanova", Nation: "RUS"},
+		Status:       model.StatusFinished,
+		Shootings:    shootings,
+		PenaltySpeed: penaltySpeed,
+	}
+	for _, speed := range lapSpeeds {
+		result.Laps = append(result.Laps, model.LapResult{Speed: speed})
+	}
+	c := classification.Classification{
+		Name:      classification.OverallName,
+		Standings: []classification.Standing{{Rank: 1, Result: result}},
+	}
+	return Race{Name: name, Document: classification.Document{Classifications: []classification.Classification{c}}}
+}
+
+func TestBuild(t *testing.T) {
+	races := []Race{
+		testRace("1", []float64{5, 6}, []model.Shooting{
+			{FiringRange: 1, Hits: 3, Shots: 5, Start: 1000, End: 41000},
+			{FiringRange: 2, Hits: 5, Shots: 5, Start: 100000, End: 130000},
+		}, 2),
+		testRace("2", []float64{6, 7}, []model.Shooting{
+			{FiringRange: 1, Hits: 4, Shots: 5, Start: 1000, End: 36000},
+			{FiringRange: 1, Hits: 5, Shots: 5, Start: 100000, End: 125000},
+		}, 0),
+		testRace("3", []float64{7, 8}, nil, 3),
+	}
+	histories := Build(races)
+	if len(histories) != 1 {
+		t.Fatalf("Expected history of one athlete, got %d", len(histories))
+	}
+	h := histories[0]
+	if h.Athlete != (season.Athlete{Name: "Anna Ivanova", Nation: "RUS"}) || len(h.Races) != 3 {
+		t.Fatalf("Unexpected history %+v", h)
+	}
+
+	first := h.Races[0]
+	if first.LapSpeed != 5.5 || first.Accuracy != 0.8 || first.RangeTime != 70000 {
+		t.Errorf("Unexpected first race %+v", first)
+	}
+	if want := []float64{0.7, 1}; !almostEqual(h.StageAccuracy, want) {
+		t.Errorf("Expected stage accuracy %v, got %v", want, h.StageAccuracy)
+	}
+	want := Trend{LapSpeed: 1, Accuracy: 0.1, RangeTime: -10000, PenaltySpeed: 1}
+	got := []float64{h.Trend.LapSpeed, h.Trend.Accuracy, h.Trend.RangeTime, h.Trend.PenaltySpeed}
+	if !almostEqual(got, []float64{want.LapSpeed, want.Accuracy, want.RangeTime, want.PenaltySpeed}) {
+		t.Errorf("Expected trend %+v, got %+v", want, h.Trend)
+	}
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b, histories); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `athlete,race,status,rank,lapSpeed,accuracy,rangeTime,penaltySpeed,stages
+Anna Ivanova (RUS),1,Finished,1,5.500,0.800,00:01:10.000,2.000,3/5 5/5
+Anna Ivanova (RUS),2,Finished,1,6.500,0.900,00:01:00.000,0.000,4/5 5/5
+Anna Ivanova (RUS),3,Finished,1,7.500,0.000,00:00:00.000,3.000,
+`
+	if b.String() != wantCSV {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", wantCSV, b.String())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	races, err := Load("../service/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(races) == 0 || races[0].Name != "categories" {
+		t.Fatalf("Expected races ordered by name, got %d", len(races))
+	}
+	for _, h := range Build(races) {
+		for _, e := range h.Races {
+			if e.Status == model.StatusFinished && (len(e.Stages) == 0 || e.RangeTime <= 0) {
+				t.Errorf("Expected shooting stages of %s in %s, got %+v", h.Athlete, e.Race, e)
+			}
+		}
+	}
+}
+
+func almostEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
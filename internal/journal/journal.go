@@ -0,0 +1,169 @@
+// Package journal is a durable append-only event journal
+package journal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrCorruptRecord is returned when a record checksum or sequence doesn't match
+	ErrCorruptRecord   Err = "journal record is corrupted"
+	errMultilineRecord Err = "journal record can't contain line breaks"
+)
+
+const (
+	fieldSep  = "\t"
+	recordSep = '\n'
+)
+
+// Record is a single journaled event
+type Record struct {
+	Seq  int
+	Line string
+}
+
+// Journal is an append-only file of event records.
+// Every record is written as "<seq>\t<crc32>\t<line>\n" and synced to disk
+// before Append returns.
+type Journal struct {
+	file *os.File
+	seq  int
+}
+
+// Open opens or creates journal at the path.
+// Records are verified on open; an incomplete trailing record left by a crash
+// is truncated, any other damage is reported as ErrCorruptRecord.
+func Open(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, size, err := scan(file, nil)
+	if err == nil {
+		err = file.Truncate(size)
+	}
+	if err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+
+	return &Journal{
+		file: file,
+		seq:  seq,
+	}, nil
+}
+
+// Seq returns sequence number of the last record
+func (j *Journal) Seq() int {
+	return j.seq
+}
+
+// Append writes next record and syncs it to disk
+func (j *Journal) Append(line string) error {
+	if strings.ContainsRune(line, recordSep) {
+		return errMultilineRecord
+	}
+	if _, err := j.file.WriteString(encode(Record{Seq: j.seq + 1, Line: line})); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.seq++
+	return nil
+}
+
+// Replay calls fn for every record in order
+func (j *Journal) Replay(fn func(Record) error) error {
+	file, err := os.Open(j.file.Name())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, _, err = scan(file, fn)
+	return err
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+func encode(rec Record) string {
+	return fmt.Sprintf("%d%s%08x%s%s%c", rec.Seq, fieldSep, checksum(rec), fieldSep, rec.Line, recordSep)
+}
+
+func checksum(rec Record) uint32 {
+	return crc32.ChecksumIEEE([]byte(strconv.Itoa(rec.Seq) + fieldSep + rec.Line))
+}
+
+// scan reads records from r, returning the last sequence number
+// and the size of the valid part
+func scan(r io.Reader, fn func(Record) error) (int, int64, error) {
+	reader := bufio.NewReader(r)
+	var seq int
+	var size int64
+	for {
+		raw, err := reader.ReadString(recordSep)
+		if errors.Is(err, io.EOF) {
+			// a record without separator is a torn write
+			return seq, size, nil
+		}
+		if err != nil {
+			return seq, size, err
+		}
+
+		rec, err := decode(strings.TrimSuffix(raw, string(recordSep)))
+		if err != nil || rec.Seq != seq+1 {
+			return seq, size, fmt.Errorf("%w: record %d", ErrCorruptRecord, seq+1)
+		}
+		if fn != nil {
+			if err := fn(rec); err != nil {
+				return seq, size, err
+			}
+		}
+		seq = rec.Seq
+		size += int64(len(raw))
+	}
+}
+
+func decode(raw string) (Record, error) {
+	fields := strings.SplitN(raw, fieldSep, 3)
+	if len(fields) != 3 {
+		return Record{}, ErrCorruptRecord
+	}
+	seq, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Record{}, ErrCorruptRecord
+	}
+	sum, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return Record{}, ErrCorruptRecord
+	}
+	rec := Record{Seq: seq, Line: fields[2]}
+	if checksum(rec) != uint32(sum) {
+		return Record{}, ErrCorruptRecord
+	}
+	return rec, nil
+}
@@ -0,0 +1,132 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppendAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	lines := []string{"[09:31:49.285] 1 3", "[09:55:00.000] 2 1 10:00:00.000", "[10:00:01.744] 4 1"}
+
+	j := mustOpen(t, path)
+	for _, line := range lines {
+		if err := j.Append(line); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	mustClose(t, j)
+
+	j = mustOpen(t, path)
+	defer mustClose(t, j)
+	if j.Seq() != len(lines) {
+		t.Errorf("Expected seq %d, got %d", len(lines), j.Seq())
+	}
+
+	got := mustReplay(t, j)
+	want := []Record{{1, lines[0]}, {2, lines[1]}, {3, lines[2]}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected records %v, got %v", want, got)
+	}
+}
+
+func TestTornTailIsTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	j := mustOpen(t, path)
+	if err := j.Append("[09:31:49.285] 1 3"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	mustClose(t, j)
+	appendRaw(t, path, "2\t0000")
+
+	j = mustOpen(t, path)
+	if j.Seq() != 1 {
+		t.Errorf("Expected seq 1, got %d", j.Seq())
+	}
+	if err := j.Append("[09:32:17.531] 1 2"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	got := mustReplay(t, j)
+	mustClose(t, j)
+	if len(got) != 2 || got[1].Line != "[09:32:17.531] 1 2" {
+		t.Errorf("Unexpected records after truncation: %v", got)
+	}
+}
+
+func TestCorruptedRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"bad checksum", "1\t00000000\t[09:31:49.285] 1 3\n"},
+		{"missing fields", "1 [09:31:49.285] 1 3\n"},
+		{"sequence gap", encode(Record{Seq: 2, Line: "[09:31:49.285] 1 3"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal")
+			appendRaw(t, path, tt.raw)
+
+			_, err := Open(path)
+			if !errors.Is(err, ErrCorruptRecord) {
+				t.Errorf("Expected %v, got %v", ErrCorruptRecord, err)
+			}
+		})
+	}
+}
+
+func TestAppendMultiline(t *testing.T) {
+	j := mustOpen(t, filepath.Join(t.TempDir(), "journal"))
+	defer mustClose(t, j)
+
+	if err := j.Append("[09:31:49.285] 1 3\n[09:32:17.531] 1 2"); !errors.Is(err, errMultilineRecord) {
+		t.Errorf("Expected %v, got %v", errMultilineRecord, err)
+	}
+	if j.Seq() != 0 {
+		t.Errorf("Expected seq 0, got %d", j.Seq())
+	}
+}
+
+func mustOpen(t *testing.T, path string) *Journal {
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return j
+}
+
+func mustClose(t *testing.T, j *Journal) {
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func mustReplay(t *testing.T, j *Journal) []Record {
+	var records []Record
+	err := j.Replay(func(rec Record) error {
+		records = append(records, rec)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	return records
+}
+
+func appendRaw(t *testing.T, path, raw string) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Competitor is a start list entry
+type Competitor struct {
+	ID       int    `json:"id"`
+	Bib      int    `json:"bib,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Nation   string `json:"nation,omitempty"`
+	Club     string `json:"club,omitempty"`
+	Gender   string `json:"gender,omitempty"`
+	Category string `json:"category,omitempty"`
+
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// String returns competitor's bib, name and details skipping empty fields
+func (c Competitor) String() string {
+	parts := []string{}
+	if c.Bib != 0 {
+		parts = append(parts, fmt.Sprintf("#%d", c.Bib))
+	}
+	if c.Name != "" {
+		parts = append(parts, c.Name)
+	}
+	details := []string{}
+	for _, detail := range []string{c.Nation, c.Club, c.Gender, c.Category} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) > 0 {
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(details, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,13 +1,21 @@
 // Package model is a container for domain models
 package model
 
-// Config is a run config
+// Config is a run config, Format names the race format rules, individual by default
 type Config struct {
-	Laps          int    `json:"laps"`
-	LapLen        int    `json:"lapLen"`
-	PenaltyLen    int    `json:"penaltyLen"`
-	FiringLines   int    `json:"firingLines"`
-	Start         string `json:"start"`
-	StartDelta    string `json:"startDelta"`
+	Format        string    `json:"format,omitempty"`
+	Laps          int       `json:"laps"`
+	LapLen        int       `json:"lapLen"`
+	PenaltyLen    int       `json:"penaltyLen"`
+	FiringLines   int       `json:"firingLines"`
+	Start         string    `json:"start"`
+	StartDelta    string    `json:"startDelta"`
+	Precision     Precision `json:"precision"`
+	Rounding      Rounding  `json:"rounding"`
 	TargetsAmount int
 }
+
+// Timing returns precision and rounding of results
+func (c Config) Timing() Timing {
+	return Timing{Precision: c.Precision, Rounding: c.Rounding}
+}
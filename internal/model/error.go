@@ -17,4 +17,8 @@ const (
 	errNotOnFiringRange    Err = "not on firing range"
 	errNotAfterFiringRange Err = "started penalty not exactly after firing"
 	errQuitPenalty         Err = "not running penalty lap"
+	errStateVersion        Err = "unsupported runner state version"
+	errDisqualified        Err = "runner is disqualified"
+	errNotRunning          Err = "not running"
+	errNoTime              Err = "runner has no time to adjust"
 )
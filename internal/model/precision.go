@@ -0,0 +1,92 @@
+package model
+
+import "fmt"
+
+// Precision is a resolution of results
+type Precision string
+
+// Precisions
+const (
+	PrecisionMilliseconds Precision = "milliseconds"
+	PrecisionHundredths   Precision = "hundredths"
+	PrecisionTenths       Precision = "tenths"
+)
+
+// DefaultPrecision keeps results in milliseconds, configs opt into the official tenths
+const DefaultPrecision = PrecisionMilliseconds
+
+// Valid reports whether precision is known, empty is the default one
+func (p Precision) Valid() bool {
+	switch p {
+	case "", PrecisionMilliseconds, PrecisionHundredths, PrecisionTenths:
+		return true
+	}
+	return false
+}
+
+// unit returns precision step in milliseconds and amount of fraction digits
+func (p Precision) unit() (int, int) {
+	switch p {
+	case PrecisionTenths:
+		return 100, 1
+	case PrecisionHundredths:
+		return 10, 2
+	default:
+		return 1, 3
+	}
+}
+
+// Rounding is a mode of reducing times to the precision
+type Rounding string
+
+// Roundings
+const (
+	RoundingTruncate Rounding = "truncate"
+	RoundingRound    Rounding = "round"
+)
+
+// Valid reports whether rounding is known, empty is truncation
+func (r Rounding) Valid() bool {
+	return r == "" || r == RoundingTruncate || r == RoundingRound
+}
+
+// Timing is a precision and rounding applied to ranked and printed times
+type Timing struct {
+	Precision Precision `json:"precision"`
+	Rounding  Rounding  `json:"rounding"`
+}
+
+// RawTiming keeps times in milliseconds
+var RawTiming = Timing{Precision: PrecisionMilliseconds}
+
+// Round reduces time in milliseconds to the precision
+func (t Timing) Round(time int) int {
+	step, _ := t.Precision.unit()
+	if t.Rounding == RoundingRound {
+		time += step / 2
+	}
+	return time / step * step
+}
+
+// Format returns time as "hh:mm:ss.t" with fraction digits of the precision
+func (t Timing) Format(time int) string {
+	step, digits := t.Precision.unit()
+	time = t.Round(time)
+	return fmt.Sprintf("%02d:%02d:%02d.%0*d", time/(3600*1000), (time%(3600*1000))/(60*1000),
+		(time%(60*1000))/1000, digits, (time%1000)/step)
+}
+
+// FormatGap returns time gap as "+mm:ss.t" with fraction digits of the precision
+func (t Timing) FormatGap(gap int) string {
+	step, digits := t.Precision.unit()
+	gap = t.Round(gap)
+	return fmt.Sprintf("+%02d:%02d.%0*d", gap/(60*1000), (gap%(60*1000))/1000, digits, (gap%1000)/step)
+}
+
+// FormatAdjustment returns signed time adjustment
+func (t Timing) FormatAdjustment(delta int) string {
+	if delta < 0 {
+		return "-" + t.Format(-delta)
+	}
+	return "+" + t.Format(delta)
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status is a result status of runner
+type Status string
+
+// Result statuses
+const (
+	StatusFinished Status = "Finished"
+	// StatusLapped is a runner lapped by the leader and pulled out of the race
+	StatusLapped Status = "LAP"
+	// StatusDNF is a runner who started but did not finish
+	StatusDNF Status = "DNF"
+	// StatusDNS is a runner who did not start
+	StatusDNS Status = "DNS"
+	// StatusDSQ is a disqualified runner
+	StatusDSQ Status = "DSQ"
+	// StatusRunning is a runner on the course, never a result status:
+	// runners still running when events end are DNF
+	StatusRunning Status = "Running"
+)
+
+// statusOrder is the order of status groups in the resulting table
+var statusOrder = map[Status]int{
+	StatusFinished: 0,
+	StatusLapped:   1,
+	StatusDNF:      2,
+	StatusDNS:      3,
+	StatusDSQ:      4,
+}
+
+// Adjustment is a jury time penalty (positive) or bonus (negative)
+type Adjustment struct {
+	Delta  int    `json:"delta"`
+	Reason string `json:"reason"`
+}
+
+// Shooting is a firing range stage, Start and End are times on the range,
+// Penalty is time on penalty loops after it
+type Shooting struct {
+	FiringRange int `json:"firingRange"`
+	Hits        int `json:"hits"`
+	Shots       int `json:"shots"`
+	Start       int `json:"start"`
+	End         int `json:"end"`
+	Penalty     int `json:"penalty,omitempty"`
+}
+
+// LapResult is a completed main lap
+type LapResult struct {
+	Time  int     `json:"time"`
+	Speed float64 `json:"speed"`
+}
+
+// Result is a run result of runner.
+// Times are raw milliseconds, AdjustedTime includes jury adjustments,
+// StartTime is the actual start time of started runner.
+// Lapped runners have the time of their last completed lap
+type Result struct {
+	RunnerID     int          `json:"runnerID"`
+	Competitor   Competitor   `json:"competitor,omitzero"`
+	Status       Status       `json:"status"`
+	StartTime    int          `json:"startTime,omitempty"`
+	Reason       string       `json:"reason,omitempty"`
+	TotalTime    int          `json:"totalTime"`
+	AdjustedTime int          `json:"adjustedTime"`
+	Adjustments  []Adjustment `json:"adjustments,omitempty"`
+	TotalLaps    int          `json:"totalLaps"`
+	Laps         []LapResult  `json:"laps"`
+	PenaltyTime  int          `json:"penaltyTime"`
+	PenaltySpeed float64      `json:"penaltySpeed"`
+	Hits         int          `json:"hits"`
+	Shots        int          `json:"shots"`
+	Shootings    []Shooting   `json:"shootings,omitempty"`
+}
+
+// Less reports whether r goes before other in the resulting table:
+// finished by time, lapped by laps and time, DNF by laps, then DNS and DSQ.
+// Times are compared after timing rounding, ties are broken by bib and runner ID,
+// competitors without a bib go after the ones with it
+func (r Result) Less(other Result, timing Timing) bool {
+	if r.Status != other.Status {
+		return statusOrder[r.Status] < statusOrder[other.Status]
+	}
+	if r.Status == StatusLapped || r.Status == StatusDNF {
+		if len(r.Laps) != len(other.Laps) {
+			return len(r.Laps) > len(other.Laps)
+		}
+	}
+	if r.Status == StatusFinished || r.Status == StatusLapped {
+		if time, otherTime := timing.Round(r.AdjustedTime), timing.Round(other.AdjustedTime); time != otherTime {
+			return time < otherTime
+		}
+	}
+	if bib, otherBib := r.Competitor.Bib, other.Competitor.Bib; bib != otherBib {
+		// competitors without a bib go after the ones with it
+		if bib == 0 || otherBib == 0 {
+			return otherBib == 0
+		}
+		return bib < otherBib
+	}
+	return r.RunnerID < other.RunnerID
+}
+
+// Tied reports whether r and other share the rank after timing rounding
+func (r Result) Tied(other Result, timing Timing) bool {
+	return r.Status == other.Status && len(r.Laps) == len(other.Laps) &&
+		timing.Round(r.AdjustedTime) == timing.Round(other.AdjustedTime)
+}
+
+// String returns result table row with times in milliseconds
+func (r Result) String() string {
+	return r.Format(RawTiming)
+}
+
+// Format returns result table row with times reduced by the timing
+func (r Result) Format(timing Timing) string {
+	lapResults := []string{}
+	for _, lap := range r.Laps {
+		lapResults = append(lapResults, fmt.Sprintf("{%s,%f}", timing.Format(lap.Time), lap.Speed))
+	}
+	for i := len(r.Laps); i < r.TotalLaps; i++ {
+		lapResults = append(lapResults, "{,}")
+	}
+	lapResultsS := strings.Join(lapResults, ", ")
+
+	runner := strconv.Itoa(r.RunnerID)
+	if competitor := r.Competitor.String(); competitor != "" {
+		runner += " " + competitor
+	}
+
+	var row string
+	switch r.Status {
+	case StatusFinished:
+		row = fmt.Sprintf("[%s] %s [%s] {%s, %f} %d/%d", timing.Format(r.AdjustedTime), runner, lapResultsS, timing.Format(r.PenaltyTime), r.PenaltySpeed, r.Hits, r.Shots)
+		if len(r.Adjustments) > 0 {
+			adjustments := []string{timing.Format(r.TotalTime)}
+			for _, adjustment := range r.Adjustments {
+				adjustments = append(adjustments, fmt.Sprintf("%s %s", timing.FormatAdjustment(adjustment.Delta), adjustment.Reason))
+			}
+			row += fmt.Sprintf(" {%s}", strings.Join(adjustments, ", "))
+		}
+		return row
+	case StatusDNS:
+		row = fmt.Sprintf("[%s] %s [%s] {,} 0/0", r.Status, runner, lapResultsS)
+	default:
+		row = fmt.Sprintf("[%s] %s [%s] {%s, %f} %d/%d", r.Status, runner, lapResultsS, timing.Format(r.PenaltyTime), r.PenaltySpeed, r.Hits, r.Shots)
+	}
+	if r.Reason != "" {
+		row += fmt.Sprintf(" (%s)", r.Reason)
+	}
+	return row
+}
@@ -1,7 +1,7 @@
 package model
 
 import (
-	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -20,14 +20,12 @@ const (
 	leftFiringRange
 	runningPenalty
 	finished
+	disqualified
+	lapped
 )
 
-type failStatus string
-
-const (
-	notStartedStatus  failStatus = "NotStarted"
-	notFinishedStatus failStatus = "NotFinished"
-)
+// LateStartReason is a disqualification reason of a competitor started too late
+const LateStartReason = "LATE_START"
 
 const timeLayout = "15:04:05"
 
@@ -58,6 +56,11 @@ type Runner struct {
 	firingRange   int
 	targetHit     int
 	targetsAmount int
+	shootings     []Shooting
+
+	adjustments []Adjustment
+	dsqReason   string
+	quitReason  string
 }
 
 // NewRunner returns new Runner
@@ -80,14 +83,10 @@ func NewRunner(
 	}, nil
 }
 
-// SetStartTime sets draw-start time for runner
-func (r *Runner) SetStartTime(time string) error {
+// SetStartTime sets draw-start time for runner, times are in milliseconds
+func (r *Runner) SetStartTime(time int) error {
 	if r.state == registered {
-		timeInt, err := formatTime(time)
-		if err != nil {
-			return err
-		}
-		r.drawStartTime = timeInt
+		r.drawStartTime = time
 		r.state = timeSet
 		return nil
 	}
@@ -104,14 +103,10 @@ func (r *Runner) OnLine() error {
 }
 
 // Start starts run at the time
-func (r *Runner) Start(time string) (bool, error) {
+func (r *Runner) Start(time int) (bool, error) {
 	if r.state == onLine {
-		timeInt, err := formatTime(time)
-		if err != nil {
-			return false, err
-		}
-		r.startDiff = timeInt - r.drawStartTime
-		r.lastFinishLineTime = timeInt
+		r.startDiff = time - r.drawStartTime
+		r.lastFinishLineTime = time
 		if r.startDiff > r.startDelta {
 			r.state = notStarted
 			return false, nil
@@ -123,10 +118,11 @@ func (r *Runner) Start(time string) (bool, error) {
 	return false, errStart
 }
 
-// StartFiring sets runner on firing range
-func (r *Runner) StartFiring(firingRange int) error {
+// StartFiring sets runner on firing range at the time
+func (r *Runner) StartFiring(time, firingRange int) error {
 	if r.state == runningMain {
 		r.firingRange = firingRange
+		r.shootings = append(r.shootings, Shooting{FiringRange: firingRange, Shots: r.targetsAmount, Start: time})
 		r.state = firing
 		return nil
 	}
@@ -137,58 +133,58 @@ func (r *Runner) StartFiring(firingRange int) error {
 func (r *Runner) HitTarget(_ int) error {
 	if r.state == firing {
 		r.targetHit++
+		r.shootings[len(r.shootings)-1].Hits++
 		return nil
 	}
 	return errNotOnFiringRange
 }
 
-// QuitFiring runner
-func (r *Runner) QuitFiring() (int, error) {
+// QuitFiring runner quits firing range at the time, returns the completed shooting
+func (r *Runner) QuitFiring(time int) (Shooting, error) {
 	if r.state == firing {
 		r.state = leftFiringRange
-		return r.firingRange, nil
+		shooting := &r.shootings[len(r.shootings)-1]
+		shooting.End = time
+		return *shooting, nil
 	}
-	return 0, errNotOnFiringRange
+	return Shooting{}, errNotOnFiringRange
+}
+
+// FiringRange returns firing range the runner is on
+func (r *Runner) FiringRange() (int, bool) {
+	return r.firingRange, r.state == firing
 }
 
 // StartPenalty runner is on penalty lap
-func (r *Runner) StartPenalty(time string) error {
+func (r *Runner) StartPenalty(time int) error {
 	if r.state == leftFiringRange {
-		timeInt, err := formatTime(time)
-		if err != nil {
-			return err
-		}
 		r.state = runningPenalty
 		r.penaltyLaps++
-		r.lastPenaltyTime = timeInt
+		r.lastPenaltyTime = time
 		return nil
 	}
 	return errNotAfterFiringRange
 }
 
-// QuitPenalty runner quits penalty lap
-func (r *Runner) QuitPenalty(time string) error {
+// QuitPenalty runner quits penalty lap, returns time spent on it
+func (r *Runner) QuitPenalty(time int) (int, error) {
 	if r.state == runningPenalty {
-		timeInt, err := formatTime(time)
-		if err != nil {
-			return err
-		}
 		r.state = runningMain
-		r.penaltyTime += timeInt - r.lastPenaltyTime
-		return nil
+		served := time - r.lastPenaltyTime
+		r.penaltyTime += served
+		if len(r.shootings) > 0 {
+			r.shootings[len(r.shootings)-1].Penalty += served
+		}
+		return served, nil
 	}
-	return errQuitPenalty
+	return 0, errQuitPenalty
 }
 
 // FinishLap runner finished another lap
-func (r *Runner) FinishLap(time string) (bool, error) {
+func (r *Runner) FinishLap(time int) (bool, error) {
 	if r.state == runningMain || r.state == leftFiringRange {
 		r.state = runningMain
-		timeInt, err := formatTime(time)
-		if err != nil {
-			return false, err
-		}
-		lapTime := timeInt - r.lastFinishLineTime
+		lapTime := time - r.lastFinishLineTime
 		r.lapTimes = append(r.lapTimes, lapTime)
 		r.avLapSpeed = append(r.avLapSpeed, float64(r.lapLen)*1000.0/float64(lapTime))
 		r.laps++
@@ -196,18 +192,68 @@ func (r *Runner) FinishLap(time string) (bool, error) {
 		if finishRunning {
 			r.state = finished
 		}
-		r.lastFinishLineTime = timeInt
+		r.lastFinishLineTime = time
 		return finishRunning, nil
 	}
 	return false, errNotRunningMainLap
 }
 
-// QuitRunning runner quit running for some reason
-func (r *Runner) QuitRunning() error {
+// QuitRunning runner quit running for the reason
+func (r *Runner) QuitRunning(reason string) error {
+	switch r.state {
+	case disqualified:
+		return errDisqualified
+	case notStarted, notFinished, finished, lapped:
+		return errNotRunning
+	}
 	r.state = notFinished
+	r.quitReason = reason
+	return nil
+}
+
+// Lapped runner is lapped by the leader and pulled out of the race
+func (r *Runner) Lapped() error {
+	switch r.state {
+	case runningMain, firing, leftFiringRange, runningPenalty:
+		r.state = lapped
+		return nil
+	}
+	return errNotRunning
+}
+
+// AdjustTime applies jury time penalty (positive delta) or bonus (negative delta) in milliseconds
+// to a runner who gets a time: still running, finished or lapped
+func (r *Runner) AdjustTime(delta int, reason string) error {
+	switch r.state {
+	case disqualified:
+		return errDisqualified
+	case registered, timeSet, onLine, notStarted, notFinished:
+		return errNoTime
+	}
+	r.adjustments = append(r.adjustments, Adjustment{Delta: delta, Reason: reason})
 	return nil
 }
 
+// Disqualify runner for the reason
+func (r *Runner) Disqualify(reason string) error {
+	if r.state == disqualified {
+		return errDisqualified
+	}
+	r.state = disqualified
+	r.dsqReason = reason
+	return nil
+}
+
+// ParseTime returns milliseconds of "hh:mm:ss.sss" time
+func ParseTime(timeStr string) (int, error) {
+	return formatTime(timeStr)
+}
+
+// ParseDuration returns milliseconds of "hh:mm:ss" duration
+func ParseDuration(durationStr string) (int, error) {
+	return formatTimeNoMill(durationStr)
+}
+
 func formatTime(timeStr string) (int, error) {
 	timeSep := strings.Split(timeStr, ".")
 	if len(timeSep) == 1 || len(timeSep[1]) != 3 {
@@ -226,6 +272,24 @@ func formatTime(timeStr string) (int, error) {
 	return totalMilliseconds, nil
 }
 
+// ParseAdjustment returns milliseconds of "+hh:mm:ss[.sss]" penalty or "-hh:mm:ss[.sss]" bonus
+func ParseAdjustment(deltaStr string) (int, error) {
+	if len(deltaStr) < 2 || (deltaStr[0] != '+' && deltaStr[0] != '-') {
+		return 0, errInvalidTimeFormat
+	}
+	delta, err := formatTime(deltaStr[1:])
+	if err != nil {
+		delta, err = formatTimeNoMill(deltaStr[1:])
+	}
+	if err != nil {
+		return 0, err
+	}
+	if deltaStr[0] == '-' {
+		delta = -delta
+	}
+	return delta, nil
+}
+
 func formatTimeNoMill(timeStr string) (int, error) {
 	t, err := time.Parse(timeLayout, timeStr)
 	if err != nil {
@@ -236,45 +300,75 @@ func formatTimeNoMill(timeStr string) (int, error) {
 	return totalMilliseconds, nil
 }
 
-func parseTime(time int) string {
-	hours := time / (3600 * 1000)
-	minutes := (time % (3600 * 1000)) / (60 * 1000)
-	seconds := (time % (60 * 1000)) / 1000
-	milliseconds := time % 1000
-
-	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, seconds, milliseconds)
+// Status returns current status of runner, StatusRunning while on the course
+func (r *Runner) Status() Status {
+	switch r.state {
+	case finished:
+		return StatusFinished
+	case lapped:
+		return StatusLapped
+	case registered, timeSet, onLine:
+		return StatusDNS
+	case notStarted, disqualified:
+		return StatusDSQ
+	case notFinished:
+		return StatusDNF
+	default:
+		return StatusRunning
+	}
 }
 
 // GetResult returns run results
-func (r *Runner) GetResult() (int, string) {
-	var result string
-	var totalTime int
-
-	lapResults := []string{}
+func (r *Runner) GetResult() Result {
+	result := Result{
+		RunnerID:    r.runnerID,
+		TotalLaps:   r.totalRaceLaps,
+		PenaltyTime: r.penaltyTime,
+		Hits:        r.targetHit,
+		Shots:       r.laps * r.targetsAmount,
+		Adjustments: slices.Clone(r.adjustments),
+		// the last shooting changes while the runner is firing
+		Shootings: slices.Clone(r.shootings),
+	}
+	if r.lastFinishLineTime > 0 {
+		result.StartTime = r.drawStartTime + r.startDiff
+	}
 	for i := 0; i < len(r.lapTimes); i++ {
-		lapResult := fmt.Sprintf("{%s,%f}", parseTime(r.lapTimes[i]), r.avLapSpeed[i])
-		lapResults = append(lapResults, lapResult)
+		result.Laps = append(result.Laps, LapResult{Time: r.lapTimes[i], Speed: r.avLapSpeed[i]})
 	}
-	for i := 0; i < r.totalRaceLaps-len(r.lapTimes); i++ {
-		lapResults = append(lapResults, "{,}")
+	if r.penaltyTime > 0 {
+		result.PenaltySpeed = float64(r.penaltyLaps*r.penaltyLapLen*1000) / float64(r.penaltyTime)
 	}
-	lapResultsS := strings.Join(lapResults, ", ")
-	if r.state == finished {
-		totalTime = r.lastFinishLineTime - r.drawStartTime
-		totalTimeS := parseTime(totalTime)
-		penaltyTime := parseTime(r.penaltyTime)
-		var avPenaltySpeed float64
-		if r.penaltyTime > 0 {
-			avPenaltySpeed = float64(r.penaltyLaps*r.penaltyLapLen*1000) / float64(r.penaltyTime)
-		}
-		result = fmt.Sprintf("[%s] %d [%s] {%s, %f} %d/%d", totalTimeS, r.runnerID, lapResultsS, penaltyTime, avPenaltySpeed, r.targetHit, r.laps*r.targetsAmount)
-	} else if r.state == notStarted {
-		result = fmt.Sprintf("[%s] %d [%s] {,} 0/0", notStartedStatus, r.runnerID, lapResultsS)
-	} else if r.state == notFinished {
-		penaltyTime := parseTime(r.penaltyTime)
-		avPenaltySpeed := float64(r.penaltyLaps*r.penaltyLapLen*1000) / float64(r.penaltyTime)
-		result = fmt.Sprintf("[%s] %d [%s] {%s, %f} %d/%d", notFinishedStatus, r.runnerID, lapResultsS, penaltyTime, avPenaltySpeed, r.targetHit, r.laps*5)
+
+	switch r.state {
+	case finished:
+		result.Status = StatusFinished
+		result.TotalTime = r.lastFinishLineTime - r.drawStartTime
+		result.AdjustedTime = r.adjusted(result.TotalTime)
+	case lapped:
+		result.Status = StatusLapped
+		result.TotalTime = r.lastFinishLineTime - r.drawStartTime
+		result.AdjustedTime = r.adjusted(result.TotalTime)
+	case registered, timeSet, onLine:
+		result.Status = StatusDNS
+	case notStarted:
+		result.Status = StatusDSQ
+		result.Reason = LateStartReason
+	case disqualified:
+		result.Status = StatusDSQ
+		result.Reason = r.dsqReason
+	default:
+		result.Status = StatusDNF
+		result.Reason = r.quitReason
 	}
 
-	return totalTime, result
+	return result
+}
+
+// adjusted returns the time with jury adjustments applied
+func (r *Runner) adjusted(time int) int {
+	for _, adjustment := range r.adjustments {
+		time += adjustment.Delta
+	}
+	return time
 }
@@ -0,0 +1,106 @@
+package model
+
+import "encoding/json"
+
+// runnerStateVersion is bumped on every incompatible change of runnerState
+const runnerStateVersion = 3
+
+// runnerState is a serializable form of Runner
+type runnerState struct {
+	Version int `json:"version"`
+
+	TotalRaceLaps int `json:"totalRaceLaps"`
+	LapLen        int `json:"lapLen"`
+	PenaltyLapLen int `json:"penaltyLapLen"`
+	StartDelta    int `json:"startDelta"`
+	RunnerID      int `json:"runnerID"`
+
+	State state `json:"state"`
+
+	DrawStartTime      int `json:"drawStartTime"`
+	StartDiff          int `json:"startDiff"`
+	LastFinishLineTime int `json:"lastFinishLineTime"`
+
+	Laps       int       `json:"laps"`
+	LapTimes   []int     `json:"lapTimes"`
+	AvLapSpeed []float64 `json:"avLapSpeed"`
+
+	LastPenaltyTime int `json:"lastPenaltyTime"`
+	PenaltyLaps     int `json:"penaltyLaps"`
+	PenaltyTime     int `json:"penaltyTime"`
+
+	FiringRange   int        `json:"firingRange"`
+	TargetHit     int        `json:"targetHit"`
+	TargetsAmount int        `json:"targetsAmount"`
+	Shootings     []Shooting `json:"shootings,omitempty"`
+
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+	DsqReason   string       `json:"dsqReason,omitempty"`
+	QuitReason  string       `json:"quitReason,omitempty"`
+}
+
+// MarshalJSON returns versioned runner state
+func (r *Runner) MarshalJSON() ([]byte, error) {
+	return json.Marshal(runnerState{
+		Version:            runnerStateVersion,
+		TotalRaceLaps:      r.totalRaceLaps,
+		LapLen:             r.lapLen,
+		PenaltyLapLen:      r.penaltyLapLen,
+		StartDelta:         r.startDelta,
+		RunnerID:           r.runnerID,
+		State:              r.state,
+		DrawStartTime:      r.drawStartTime,
+		StartDiff:          r.startDiff,
+		LastFinishLineTime: r.lastFinishLineTime,
+		Laps:               r.laps,
+		LapTimes:           r.lapTimes,
+		AvLapSpeed:         r.avLapSpeed,
+		LastPenaltyTime:    r.lastPenaltyTime,
+		PenaltyLaps:        r.penaltyLaps,
+		PenaltyTime:        r.penaltyTime,
+		FiringRange:        r.firingRange,
+		TargetHit:          r.targetHit,
+		TargetsAmount:      r.targetsAmount,
+		Shootings:          r.shootings,
+		Adjustments:        r.adjustments,
+		DsqReason:          r.dsqReason,
+		QuitReason:         r.quitReason,
+	})
+}
+
+// UnmarshalJSON restores runner from versioned state
+func (r *Runner) UnmarshalJSON(data []byte) error {
+	st := runnerState{}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return err
+	}
+	if st.Version != runnerStateVersion {
+		return errStateVersion
+	}
+
+	*r = Runner{
+		totalRaceLaps:      st.TotalRaceLaps,
+		lapLen:             st.LapLen,
+		penaltyLapLen:      st.PenaltyLapLen,
+		startDelta:         st.StartDelta,
+		runnerID:           st.RunnerID,
+		state:              st.State,
+		drawStartTime:      st.DrawStartTime,
+		startDiff:          st.StartDiff,
+		lastFinishLineTime: st.LastFinishLineTime,
+		laps:               st.Laps,
+		lapTimes:           st.LapTimes,
+		avLapSpeed:         st.AvLapSpeed,
+		lastPenaltyTime:    st.LastPenaltyTime,
+		penaltyLaps:        st.PenaltyLaps,
+		penaltyTime:        st.PenaltyTime,
+		firingRange:        st.FiringRange,
+		targetHit:          st.TargetHit,
+		targetsAmount:      st.TargetsAmount,
+		shootings:          st.Shootings,
+		adjustments:        st.Adjustments,
+		dsqReason:          st.DsqReason,
+		quitReason:         st.QuitReason,
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRunnerStateRoundTrip(t *testing.T) {
+	r, err := newTestRunner(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustSetStartTime(t, r, "10:00:00.000")
+	mustOnLine(t, r)
+	mustStart(t, r, "10:00:10.000")
+	if _, err := r.FinishLap(ms(t, "10:01:10.000")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.StartFiring(ms(t, "10:01:20.000"), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	restored := &Runner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(r, restored) {
+		t.Errorf("Expected %+v, got %+v", r, restored)
+	}
+
+	if _, err := restored.QuitFiring(ms(t, "10:02:00.000")); err != nil {
+		t.Errorf("Restored runner should continue firing: %v", err)
+	}
+}
+
+func TestRunnerStateVersion(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"version":0,"runnerID":1}`), &Runner{})
+	if err != errStateVersion {
+		t.Errorf("Expected %v, got %v", errStateVersion, err)
+	}
+}
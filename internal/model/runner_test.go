@@ -2,6 +2,7 @@ package model
 
 import (
 	"log"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -50,7 +51,7 @@ func TestHappyPathScenario(t *testing.T) {
 	}
 
 	r.lastFinishLineTime = 36010000
-	finishedRun, err := r.FinishLap("10:01:10.000")
+	finishedRun, err := r.FinishLap(ms(t, "10:01:10.000"))
 	if err != nil {
 		t.Fatalf("FinishLap failed: %v", err)
 	}
@@ -64,7 +65,7 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected lap time 60000, got %v", r.lapTimes)
 	}
 
-	if err := r.StartFiring(2); err != nil {
+	if err := r.StartFiring(ms(t, "10:01:20.000"), 2); err != nil {
 		t.Fatalf("StartFiring failed: %v", err)
 	}
 	if r.state != firing {
@@ -80,18 +81,19 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected 3 targets hit, got %d", r.targetHit)
 	}
 
-	rangeID, err := r.QuitFiring()
+	shooting, err := r.QuitFiring(ms(t, "10:02:00.000"))
 	if err != nil {
 		t.Fatalf("QuitFiring failed: %v", err)
 	}
-	if rangeID != 2 {
-		t.Errorf("Expected rangeID 2, got %d", rangeID)
+	expectedShooting := Shooting{FiringRange: 2, Hits: 3, Shots: r.targetsAmount, Start: ms(t, "10:01:20.000"), End: ms(t, "10:02:00.000")}
+	if shooting != expectedShooting {
+		t.Errorf("Expected shooting %+v, got %+v", expectedShooting, shooting)
 	}
 	if r.state != leftFiringRange {
 		t.Errorf("Expected state leftFiringRange, got %v", r.state)
 	}
 
-	if err := r.StartPenalty("10:02:10.000"); err != nil {
+	if err := r.StartPenalty(ms(t, "10:02:10.000")); err != nil {
 		t.Fatalf("StartPenalty failed: %v", err)
 	}
 	if r.state != runningPenalty {
@@ -101,9 +103,13 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected 1 penalty lap, got %d", r.penaltyLaps)
 	}
 
-	if err := r.QuitPenalty("10:02:30.000"); err != nil {
+	served, err := r.QuitPenalty(ms(t, "10:02:30.000"))
+	if err != nil {
 		t.Fatalf("QuitPenalty failed: %v", err)
 	}
+	if served != 20000 || r.shootings[0].Penalty != 20000 {
+		t.Errorf("Expected served penalty 20000 after the shooting, got %d and %d", served, r.shootings[0].Penalty)
+	}
 	if r.state != runningMain {
 		t.Errorf("Expected state runningMain, got %v", r.state)
 	}
@@ -111,7 +117,7 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected penaltyTime 20000, got %d", r.penaltyTime)
 	}
 
-	finishedRun, err = r.FinishLap("10:03:30.000")
+	finishedRun, err = r.FinishLap(ms(t, "10:03:30.000"))
 	if err != nil {
 		t.Fatalf("FinishLap failed: %v", err)
 	}
@@ -125,7 +131,7 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected second lap time 140000, got %v", r.lapTimes)
 	}
 
-	finishedRun, err = r.FinishLap("10:04:40.000")
+	finishedRun, err = r.FinishLap(ms(t, "10:04:40.000"))
 	if err != nil {
 		t.Fatalf("FinishLap failed: %v", err)
 	}
@@ -136,11 +142,11 @@ func TestHappyPathScenario(t *testing.T) {
 		t.Errorf("Expected state finished, got %v", r.state)
 	}
 
-	totalTime, result := r.GetResult()
-	if totalTime != 280000 {
-		t.Errorf("Expected total time 280000, got %d", totalTime)
+	result := r.GetResult()
+	if result.TotalTime != 280000 {
+		t.Errorf("Expected total time 280000, got %d", result.TotalTime)
 	}
-	if !strings.Contains(result, "[00:04:40.000]") {
+	if !strings.Contains(result.String(), "[00:04:40.000]") {
 		t.Errorf("Result string unexpected: %s", result)
 	}
 }
@@ -162,9 +168,12 @@ func TestLateStartScenario(t *testing.T) {
 		t.Errorf("Expected state notStarted, got %v", r.state)
 	}
 
-	_, result := r.GetResult()
-	if !strings.Contains(result, string(notStartedStatus)) {
-		t.Errorf("Expected not started status, got %s", result)
+	result := r.GetResult()
+	if result.Status != StatusDSQ || result.Reason != LateStartReason {
+		t.Errorf("Expected late start disqualification, got %s %s", result.Status, result.Reason)
+	}
+	if !strings.HasPrefix(result.String(), "[DSQ] 1") {
+		t.Errorf("Result string unexpected: %s", result)
 	}
 }
 
@@ -179,24 +188,210 @@ func TestNotFinishedScenario(t *testing.T) {
 	mustStart(t, r, "10:00:10.000")
 
 	r.lastFinishLineTime = 10000
-	_, err = r.FinishLap("10:01:10.000")
+	_, err = r.FinishLap(ms(t, "10:01:10.000"))
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := r.QuitRunning(); err != nil {
+	if err := r.QuitRunning("Lost in the forest"); err != nil {
 		t.Fatal(err)
 	}
 	if r.state != notFinished {
 		t.Errorf("Expected state notFinished, got %v", r.state)
 	}
 
-	_, result := r.GetResult()
-	if !strings.Contains(result, string(notFinishedStatus)) {
+	result := r.GetResult().String()
+	if !strings.Contains(result, string(StatusDNF)) || !strings.HasSuffix(result, "(Lost in the forest)") {
 		t.Errorf("Expected not finished status, got %s", result)
 	}
 }
 
+func TestTimeAdjustments(t *testing.T) {
+	r, err := newTestRunner(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustSetStartTime(t, r, "10:00:00.000")
+	mustOnLine(t, r)
+	mustStart(t, r, "10:00:00.000")
+	for _, lapTime := range []string{"10:01:00.000", "10:02:00.000", "10:03:00.000"} {
+		if _, err := r.FinishLap(ms(t, lapTime)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, adjustment := range []struct {
+		delta  string
+		reason string
+	}{{"+00:02:00", "EQUIPMENT"}, {"-00:00:10.500", "START"}} {
+		delta, err := ParseAdjustment(adjustment.delta)
+		if err != nil {
+			t.Fatalf("ParseAdjustment failed: %v", err)
+		}
+		if err := r.AdjustTime(delta, adjustment.reason); err != nil {
+			t.Fatalf("AdjustTime failed: %v", err)
+		}
+	}
+	if _, err := ParseAdjustment("00:00:10"); err != errInvalidTimeFormat {
+		t.Errorf("Expected %v for unsigned adjustment, got %v", errInvalidTimeFormat, err)
+	}
+	if err := r.QuitRunning("FELL"); err != errNotRunning {
+		t.Errorf("Expected %v after finish, got %v", errNotRunning, err)
+	}
+
+	result := r.GetResult()
+	result.Adjustments[0].Delta = 0
+	if result = r.GetResult(); result.Adjustments[0].Delta != 120000 {
+		t.Errorf("Expected result adjustments to be a copy, got %+v", result.Adjustments)
+	}
+	if result.TotalTime != 180000 {
+		t.Errorf("Expected total time 180000, got %d", result.TotalTime)
+	}
+	if result.AdjustedTime != 289500 {
+		t.Errorf("Expected adjusted time 289500, got %d", result.AdjustedTime)
+	}
+	if !strings.HasSuffix(result.String(), "{00:03:00.000, +00:02:00.000 EQUIPMENT, -00:00:10.500 START}") {
+		t.Errorf("Result string unexpected: %s", result)
+	}
+}
+
+func TestTimeAdjustmentRejected(t *testing.T) {
+	r, err := newTestRunner(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AdjustTime(120000, "EQUIPMENT"); err != errNoTime {
+		t.Errorf("Expected %v before start, got %v", errNoTime, err)
+	}
+	mustSetStartTime(t, r, "10:00:00.000")
+	mustOnLine(t, r)
+	mustStart(t, r, "10:00:00.000")
+	if err := r.QuitRunning("FELL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AdjustTime(120000, "EQUIPMENT"); err != errNoTime {
+		t.Errorf("Expected %v after quitting, got %v", errNoTime, err)
+	}
+	if adjustments := r.GetResult().Adjustments; len(adjustments) != 0 {
+		t.Errorf("Expected no adjustments, got %+v", adjustments)
+	}
+}
+
+func TestDisqualifyScenario(t *testing.T) {
+	r, err := newTestRunner(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustSetStartTime(t, r, "10:00:00.000")
+	mustOnLine(t, r)
+	mustStart(t, r, "10:00:00.000")
+	if err := r.Disqualify("EQUIPMENT"); err != nil {
+		t.Fatalf("Disqualify failed: %v", err)
+	}
+	if err := r.Disqualify("EQUIPMENT"); err != errDisqualified {
+		t.Errorf("Expected %v, got %v", errDisqualified, err)
+	}
+	if err := r.QuitRunning(""); err != errDisqualified {
+		t.Errorf("Expected %v, got %v", errDisqualified, err)
+	}
+
+	result := r.GetResult()
+	if result.Status != StatusDSQ || result.Reason != "EQUIPMENT" {
+		t.Errorf("Expected disqualified for EQUIPMENT, got %s %s", result.Status, result.Reason)
+	}
+	if !strings.HasPrefix(result.String(), "[DSQ] 1") {
+		t.Errorf("Result string unexpected: %s", result)
+	}
+}
+
+func TestLappedScenario(t *testing.T) {
+	r, err := newTestRunner(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustSetStartTime(t, r, "10:00:00.000")
+	mustOnLine(t, r)
+	if err := r.Lapped(); err != errNotRunning {
+		t.Errorf("Expected %v before start, got %v", errNotRunning, err)
+	}
+	mustStart(t, r, "10:00:00.000")
+	if _, err := r.FinishLap(ms(t, "10:01:00.000")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Lapped(); err != nil {
+		t.Fatalf("Lapped failed: %v", err)
+	}
+	if err := r.QuitRunning(""); err != errNotRunning {
+		t.Errorf("Expected %v after lapped, got %v", errNotRunning, err)
+	}
+	if err := r.AdjustTime(30000, "EQUIPMENT"); err != nil {
+		t.Fatalf("AdjustTime failed: %v", err)
+	}
+
+	result := r.GetResult()
+	if result.Status != StatusLapped || result.TotalTime != 60000 || result.AdjustedTime != 90000 {
+		t.Errorf("Expected lapped after 60000 adjusted to 90000, got %s %d %d", result.Status, result.TotalTime, result.AdjustedTime)
+	}
+}
+
+func TestResultOrder(t *testing.T) {
+	ordered := []Result{
+		{RunnerID: 4, Status: StatusFinished, AdjustedTime: 100},
+		{RunnerID: 3, Status: StatusFinished, AdjustedTime: 250, Competitor: Competitor{Bib: 1}},
+		{RunnerID: 2, Status: StatusFinished, AdjustedTime: 200, Competitor: Competitor{Bib: 2}},
+		{RunnerID: 11, Status: StatusFinished, AdjustedTime: 270, Competitor: Competitor{Bib: 3}},
+		{RunnerID: 10, Status: StatusFinished, AdjustedTime: 270},
+		{RunnerID: 12, Status: StatusFinished, AdjustedTime: 270},
+		{RunnerID: 9, Status: StatusFinished, AdjustedTime: 300},
+		{RunnerID: 1, Status: StatusLapped, Laps: make([]LapResult, 2), AdjustedTime: 300},
+		{RunnerID: 5, Status: StatusLapped, Laps: make([]LapResult, 1), AdjustedTime: 100},
+		{RunnerID: 7, Status: StatusDNF, Laps: make([]LapResult, 1)},
+		{RunnerID: 6, Status: StatusDNF},
+		{RunnerID: 8, Status: StatusDNS},
+		{RunnerID: 0, Status: StatusDSQ},
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			if got := ordered[i].Less(ordered[j], Timing{Precision: PrecisionTenths}); got != (i < j) {
+				t.Errorf("Less(%d, %d) = %v, want %v", ordered[i].RunnerID, ordered[j].RunnerID, got, i < j)
+			}
+		}
+	}
+}
+
+func TestTiming(t *testing.T) {
+	tests := []struct {
+		timing Timing
+		round  int
+		format string
+		gap    string
+	}{
+		{RawTiming, 65459, "00:01:05.459", "+01:05.459"},
+		{Timing{Precision: PrecisionHundredths}, 65450, "00:01:05.45", "+01:05.45"},
+		{Timing{Precision: PrecisionHundredths, Rounding: RoundingRound}, 65460, "00:01:05.46", "+01:05.46"},
+		{Timing{Precision: PrecisionTenths}, 65400, "00:01:05.4", "+01:05.4"},
+		{Timing{Precision: PrecisionTenths, Rounding: RoundingRound}, 65500, "00:01:05.5", "+01:05.5"},
+		{Timing{}, 65459, "00:01:05.459", "+01:05.459"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.timing.Precision)+string(tt.timing.Rounding), func(t *testing.T) {
+			if got := tt.timing.Round(65459); got != tt.round {
+				t.Errorf("Round(65459) = %d, want %d", got, tt.round)
+			}
+			if got := tt.timing.Format(65459); got != tt.format {
+				t.Errorf("Format(65459) = %q, want %q", got, tt.format)
+			}
+			if got := tt.timing.FormatGap(65459); got != tt.gap {
+				t.Errorf("FormatGap(65459) = %q, want %q", got, tt.gap)
+			}
+		})
+	}
+}
+
 func TestInvalidStateTransitions(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -220,7 +415,7 @@ func TestInvalidStateTransitions(t *testing.T) {
 				mustSetStartTime(t, r, "10:00:00.000")
 			},
 			operation: func(r *Runner) error {
-				_, err := r.Start("10:00:10.000")
+				_, err := r.Start(ms(t, "10:00:10.000"))
 				return err
 			},
 			expected: errStart,
@@ -235,7 +430,7 @@ func TestInvalidStateTransitions(t *testing.T) {
 				r.state = onLine
 			},
 			operation: func(r *Runner) error {
-				return r.StartFiring(1)
+				return r.StartFiring(ms(t, "10:01:00.000"), 1)
 			},
 			expected: errNotRunningMainLap,
 		},
@@ -259,7 +454,7 @@ func TestInvalidStateTransitions(t *testing.T) {
 				mustStart(t, r, "10:00:10.000")
 			},
 			operation: func(r *Runner) error {
-				_, err := r.QuitFiring()
+				_, err := r.QuitFiring(ms(t, "10:01:00.000"))
 				return err
 			},
 			expected: errNotOnFiringRange,
@@ -272,7 +467,7 @@ func TestInvalidStateTransitions(t *testing.T) {
 				mustStart(t, r, "10:00:10.000")
 			},
 			operation: func(r *Runner) error {
-				return r.StartPenalty("10:01:00.000")
+				return r.StartPenalty(ms(t, "10:01:00.000"))
 			},
 			expected: errNotAfterFiringRange,
 		},
@@ -284,7 +479,8 @@ func TestInvalidStateTransitions(t *testing.T) {
 				mustStart(t, r, "10:00:10.000")
 			},
 			operation: func(r *Runner) error {
-				return r.QuitPenalty("10:01:00.000")
+				_, err := r.QuitPenalty(ms(t, "10:01:00.000"))
+				return err
 			},
 			expected: errQuitPenalty,
 		},
@@ -297,7 +493,7 @@ func TestInvalidStateTransitions(t *testing.T) {
 				r.state = firing
 			},
 			operation: func(r *Runner) error {
-				_, err := r.FinishLap("10:01:00.000")
+				_, err := r.FinishLap(ms(t, "10:01:00.000"))
 				return err
 			},
 			expected: errNotRunningMainLap,
@@ -355,7 +551,7 @@ func TestTimeFormatting(t *testing.T) {
 	}
 }
 
-func TestParseTime(t *testing.T) {
+func TestRawTimingFormat(t *testing.T) {
 	tests := []struct {
 		input    int
 		expected string
@@ -368,9 +564,116 @@ func TestParseTime(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.expected, func(t *testing.T) {
-			got := parseTime(tt.input)
+			got := RawTiming.Format(tt.input)
 			if got != tt.expected {
-				t.Errorf("parseTime(%d) = %q, want %q", tt.input, got, tt.expected)
+				t.Errorf("Format(%d) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// runnerStep is a call of a Runner method in a race
+type runnerStep struct {
+	method string
+	call   func(r *Runner) error
+}
+
+// benchmarkRace returns steps of a 3 lap race of a runner missing one target per firing range
+func benchmarkRace() []runnerStep {
+	const start = 10 * 3600 * 1000
+	steps := []runnerStep{
+		{"SetStartTime", func(r *Runner) error { return r.SetStartTime(start) }},
+		{"OnLine", func(r *Runner) error { return r.OnLine() }},
+		{"Start", func(r *Runner) error {
+			_, err := r.Start(start + 1000)
+			return err
+		}},
+	}
+	now := start + 1000
+	for lap := range 3 {
+		now += 300000
+		lapEnd := now
+		steps = append(steps, runnerStep{"FinishLap", func(r *Runner) error {
+			_, err := r.FinishLap(lapEnd)
+			return err
+		}})
+		if lap == 2 {
+			break
+		}
+		firingStart := now
+		steps = append(steps, runnerStep{"StartFiring", func(r *Runner) error { return r.StartFiring(firingStart, 1) }})
+		for target := 1; target <= 4; target++ {
+			steps = append(steps, runnerStep{"HitTarget", func(r *Runner) error { return r.HitTarget(target) }})
+		}
+		now += 30000
+		firingEnd := now
+		steps = append(steps,
+			runnerStep{"QuitFiring", func(r *Runner) error {
+				_, err := r.QuitFiring(firingEnd)
+				return err
+			}},
+			runnerStep{"StartPenalty", func(r *Runner) error { return r.StartPenalty(firingEnd) }},
+		)
+		now += 25000
+		penaltyEnd := now
+		steps = append(steps, runnerStep{"QuitPenalty", func(r *Runner) error {
+			_, err := r.QuitPenalty(penaltyEnd)
+			return err
+		}})
+	}
+	return append(steps, runnerStep{"GetResult", func(r *Runner) error {
+		_ = r.GetResult()
+		return nil
+	}})
+}
+
+// BenchmarkRunner reports cost of a call of every Runner method averaged over its calls
+// in a race. Every call is made on a copy of the runner state taken before it in the race
+// cloneRunner copies the runner with its slices, so appends to the copy don't
+// overwrite results of the other copies sharing backing arrays
+func cloneRunner(r Runner) Runner {
+	r.lapTimes = slices.Clone(r.lapTimes)
+	r.avLapSpeed = slices.Clone(r.avLapSpeed)
+	r.shootings = slices.Clone(r.shootings)
+	r.adjustments = slices.Clone(r.adjustments)
+	return r
+}
+
+func BenchmarkRunner(b *testing.B) {
+	config := Config{Laps: 3, LapLen: 1000, PenaltyLen: 200, StartDelta: "00:00:30", TargetsAmount: 5}
+	r, err := NewRunner(config, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	type call struct {
+		before Runner
+		step   runnerStep
+	}
+	var methods []string
+	calls := make(map[string][]call)
+	for _, step := range benchmarkRace() {
+		if _, ok := calls[step.method]; !ok {
+			methods = append(methods, step.method)
+		}
+		calls[step.method] = append(calls[step.method], call{before: cloneRunner(*r), step: step})
+		if err := step.call(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, method := range methods {
+		b.Run(method, func(b *testing.B) {
+			methodCalls := calls[method]
+			var runner Runner
+			b.ReportAllocs()
+			i := 0
+			for b.Loop() {
+				c := &methodCalls[i%len(methodCalls)]
+				runner = cloneRunner(c.before)
+				if err := c.step.call(&runner); err != nil {
+					b.Fatal(err)
+				}
+				i++
 			}
 		})
 	}
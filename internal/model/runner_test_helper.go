@@ -15,7 +15,7 @@ func newTestRunner(t *testing.T) (*Runner, error) {
 }
 
 func mustSetStartTime(t *testing.T, r *Runner, timeStr string) {
-	if err := r.SetStartTime(timeStr); err != nil {
+	if err := r.SetStartTime(ms(t, timeStr)); err != nil {
 		t.Fatalf("SetStartTime failed: %v", err)
 	}
 }
@@ -27,9 +27,17 @@ func mustOnLine(t *testing.T, r *Runner) {
 }
 
 func mustStart(t *testing.T, r *Runner, timeStr string) bool {
-	started, err := r.Start(timeStr)
+	started, err := r.Start(ms(t, timeStr))
 	if err != nil {
 		t.Fatalf("Start failed: %v", err)
 	}
 	return started
 }
+
+func ms(t *testing.T, timeStr string) int {
+	time, err := ParseTime(timeStr)
+	if err != nil {
+		t.Fatalf("ParseTime failed: %v", err)
+	}
+	return time
+}
@@ -0,0 +1,120 @@
+// Package outgoing describes events generated by the race engine and sinks consuming them
+package outgoing
+
+import (
+	"fmt"
+	"racingMetrics/internal/model"
+)
+
+// Kind of outgoing event
+type Kind string
+
+// Outgoing event kinds. Most of them confirm an accepted incoming event,
+// Disqualified and Finished are generated by the engine
+const (
+	Registered       Kind = "registered"
+	StartTimeSet     Kind = "startTimeSet"
+	OnStartLine      Kind = "onStartLine"
+	Started          Kind = "started"
+	OnFiringRange    Kind = "onFiringRange"
+	TargetHit        Kind = "targetHit"
+	LeftFiringRange  Kind = "leftFiringRange"
+	EnteredPenalty   Kind = "enteredPenalty"
+	LeftPenalty      Kind = "leftPenalty"
+	EndedMainLap     Kind = "endedMainLap"
+	CantContinue     Kind = "cantContinue"
+	TimeAdjusted     Kind = "timeAdjusted"
+	JuryDisqualified Kind = "juryDisqualified"
+	Lapped           Kind = "lapped"
+	TimeAmended      Kind = "timeAmended"
+	ParamAmended     Kind = "paramAmended"
+	Voided           Kind = "voided"
+	UnknownEvent     Kind = "unknownEvent"
+	Disqualified     Kind = "disqualified"
+	Finished         Kind = "finished"
+)
+
+// Event is an outgoing event, times are in milliseconds since midnight.
+// Only details of the event kind are set
+type Event struct {
+	// Race is set for events of races hosted together
+	Race string `json:"race,omitempty"`
+	// Seq is a number of the incoming event which caused the outgoing one
+	Seq        int  `json:"seq"`
+	Time       int  `json:"time"`
+	Kind       Kind `json:"kind"`
+	Competitor int  `json:"competitor"`
+	// Details of the competitor from the start list
+	Details *model.Competitor `json:"details,omitempty"`
+
+	StartTime   int    `json:"startTime,omitempty"`
+	FiringRange int    `json:"firingRange,omitempty"`
+	Target      int    `json:"target,omitempty"`
+	Delta       int    `json:"delta,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	// RefSeq is a number of the event corrected by the jury
+	RefSeq   int    `json:"refSeq,omitempty"`
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+}
+
+// String returns human readable log line of the event
+func (e Event) String() string {
+	if e.Race != "" {
+		return fmt.Sprintf("[%s] (%s) %s", model.RawTiming.Format(e.Time), e.Race, e.message())
+	}
+	return fmt.Sprintf("[%s] %s", model.RawTiming.Format(e.Time), e.message())
+}
+
+// competitor returns the competitor with start list details if any
+func (e Event) competitor() string {
+	if e.Details == nil || e.Details.String() == "" {
+		return fmt.Sprintf("competitor(%d)", e.Competitor)
+	}
+	return fmt.Sprintf("competitor(%d) %s", e.Competitor, e.Details)
+}
+
+func (e Event) message() string {
+	switch e.Kind {
+	case Registered:
+		return fmt.Sprintf("The %s registered", e.competitor())
+	case StartTimeSet:
+		return fmt.Sprintf("The start time for the %s was set by a draw to %s", e.competitor(), model.RawTiming.Format(e.StartTime))
+	case OnStartLine:
+		return fmt.Sprintf("The %s is on the start line", e.competitor())
+	case Started:
+		return fmt.Sprintf("The %s has started", e.competitor())
+	case OnFiringRange:
+		return fmt.Sprintf("The %s is on the firing range(%d)", e.competitor(), e.FiringRange)
+	case TargetHit:
+		return fmt.Sprintf("The target(%d) has been hit by %s", e.Target, e.competitor())
+	case LeftFiringRange:
+		return fmt.Sprintf("The %s left the firing range", e.competitor())
+	case EnteredPenalty:
+		return fmt.Sprintf("The %s entered the penalty laps", e.competitor())
+	case LeftPenalty:
+		return fmt.Sprintf("The %s left the penalty laps", e.competitor())
+	case EndedMainLap:
+		return fmt.Sprintf("The %s ended the main lap", e.competitor())
+	case CantContinue:
+		return fmt.Sprintf("The %s can`t continue: %s", e.competitor(), e.Reason)
+	case TimeAdjusted:
+		return fmt.Sprintf("The %s got a time adjustment %s: %s", e.competitor(), model.RawTiming.FormatAdjustment(e.Delta), e.Reason)
+	case JuryDisqualified:
+		return fmt.Sprintf("The %s is disqualified by the jury: %s", e.competitor(), e.Reason)
+	case Lapped:
+		return fmt.Sprintf("The %s is lapped", e.competitor())
+	case TimeAmended:
+		return fmt.Sprintf("The jury corrected event(%d) of %s: time %s -> %s", e.RefSeq, e.competitor(), e.OldValue, e.NewValue)
+	case ParamAmended:
+		return fmt.Sprintf("The jury corrected event(%d) of %s: parameter %s -> %s", e.RefSeq, e.competitor(), e.OldValue, e.NewValue)
+	case Voided:
+		return fmt.Sprintf("The jury corrected event(%d) of %s: voided", e.RefSeq, e.competitor())
+	case Disqualified:
+		return fmt.Sprintf("The %s is disqualified", e.competitor())
+	case Finished:
+		return fmt.Sprintf("The %s has finished", e.competitor())
+	default:
+		return fmt.Sprintf("No such event for %s", e.competitor())
+	}
+}
@@ -0,0 +1,162 @@
+package outgoing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const errUnknownFormat Err = "unknown outgoing events format"
+
+// Format of outgoing events written by a sink
+type Format string
+
+// Formats
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Sink consumes outgoing events
+type Sink interface {
+	Emit(e Event) error
+}
+
+// SinkFunc is a function used as Sink
+type SinkFunc func(e Event) error
+
+// Emit calls f
+func (f SinkFunc) Emit(e Event) error {
+	return f(e)
+}
+
+// Discard is a Sink ignoring events
+var Discard Sink = SinkFunc(func(Event) error { return nil })
+
+// NewSink returns Sink writing events to w in the format
+func NewSink(w io.Writer, format Format) (Sink, error) {
+	switch format {
+	case FormatText:
+		return NewTextSink(w), nil
+	case FormatJSON:
+		return NewJSONSink(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+}
+
+// TextSink writes human readable log lines
+type TextSink struct {
+	w io.Writer
+}
+
+// NewTextSink returns TextSink of w
+func NewTextSink(w io.Writer) *TextSink {
+	return &TextSink{w: w}
+}
+
+// Emit writes log line of the event
+func (s *TextSink) Emit(e Event) error {
+	_, err := fmt.Fprintln(s.w, e)
+	return err
+}
+
+// JSONSink writes events as JSON Lines
+type JSONSink struct {
+	encoder *json.Encoder
+}
+
+// NewJSONSink returns JSONSink of w
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{encoder: json.NewEncoder(w)}
+}
+
+// Emit writes JSON line of the event
+func (s *JSONSink) Emit(e Event) error {
+	return s.encoder.Encode(e)
+}
+
+// FileSink writes events to a file
+type FileSink struct {
+	Sink
+	file *os.File
+}
+
+// NewFileSink creates or truncates file at path and returns FileSink writing to it in the format
+func NewFileSink(path string, format Format) (*FileSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := NewSink(file, format)
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	return &FileSink{Sink: sink, file: file}, nil
+}
+
+// Close syncs and closes the file
+func (s *FileSink) Close() error {
+	if err := s.file.Sync(); err != nil {
+		return errors.Join(err, s.file.Close())
+	}
+	return s.file.Close()
+}
+
+// ChanSink sends events to a channel, blocking until they are received
+type ChanSink chan<- Event
+
+// Emit sends the event
+func (s ChanSink) Emit(e Event) error {
+	s <- e
+	return nil
+}
+
+// MultiSink emits every event to all sinks
+type MultiSink []Sink
+
+// Emit emits the event to all sinks, joining their errors
+func (m MultiSink) Emit(e Event) error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Emit(e))
+	}
+	return errors.Join(errs...)
+}
+
+// RaceSink returns Sink tagging events with the race before emitting them to sink
+func RaceSink(race string, sink Sink) Sink {
+	return SinkFunc(func(e Event) error {
+		e.Race = race
+		return sink.Emit(e)
+	})
+}
+
+// SyncSink serializes events of concurrent emitters
+type SyncSink struct {
+	mu   sync.Mutex
+	sink Sink
+}
+
+// NewSyncSink returns SyncSink of sink
+func NewSyncSink(sink Sink) *SyncSink {
+	return &SyncSink{sink: sink}
+}
+
+// Emit emits the event holding the lock
+func (s *SyncSink) Emit(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sink.Emit(e)
+}
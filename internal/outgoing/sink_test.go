@@ -0,0 +1,117 @@
+package outgoing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testEvents = []Event{
+	{Seq: 1, Time: 32759867, Kind: Registered, Competitor: 1},
+	{Seq: 2, Time: 33300841, Kind: StartTimeSet, Competitor: 1, StartTime: 34200000},
+	{Seq: 5, Time: 34200500, Kind: Disqualified, Competitor: 1, Reason: "LATE_START"},
+}
+
+func TestTextSink(t *testing.T) {
+	var b bytes.Buffer
+	sink := NewTextSink(&b)
+	for _, e := range testEvents {
+		if err := sink.Emit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := `[09:05:59.867] The competitor(1) registered
+[09:15:00.841] The start time for the competitor(1) was set by a draw to 09:30:00.000
+[09:30:00.500] The competitor(1) is disqualified
+`
+	if b.String() != want {
+		t.Errorf("Expected log:\n%s\ngot:\n%s", want, b.String())
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	var b bytes.Buffer
+	sink := NewJSONSink(&b)
+	for _, e := range testEvents {
+		if err := sink.Emit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []Event
+	decoder := json.NewDecoder(&b)
+	for decoder.More() {
+		e := Event{}
+		if err := decoder.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, testEvents) {
+		t.Errorf("Expected %+v, got %+v", testEvents, got)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path, FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Emit(testEvents[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := testEvents[0].String() + "\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+	if _, err := NewFileSink(path, "xml"); !errors.Is(err, errUnknownFormat) {
+		t.Errorf("Expected %v, got %v", errUnknownFormat, err)
+	}
+}
+
+func TestChanAndMultiSink(t *testing.T) {
+	ch := make(chan Event, len(testEvents))
+	var count int
+	sink := MultiSink{ChanSink(ch), SinkFunc(func(Event) error {
+		count++
+		return nil
+	})}
+	for _, e := range testEvents {
+		if err := sink.Emit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(ch)
+
+	var got []Event
+	for e := range ch {
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, testEvents) || count != len(testEvents) {
+		t.Errorf("Expected every event in both sinks, got %d and %d", len(got), count)
+	}
+}
+
+func TestRaceSink(t *testing.T) {
+	var b bytes.Buffer
+	sink := NewSyncSink(RaceSink("women", NewTextSink(&b)))
+	if err := sink.Emit(testEvents[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := "[09:05:59.867] (women) The competitor(1) registered\n"
+	if b.String() != want {
+		t.Errorf("Expected log %q, got %q", want, b.String())
+	}
+}
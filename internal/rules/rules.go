@@ -0,0 +1,114 @@
+// Package rules is a registry of race formats and their competitor state machines
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"racingMetrics/internal/model"
+	"sort"
+	"sync"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const errUnknownFormat Err = "unknown race format"
+
+// Individual is the default race format: competitors start by draw
+// at their own start times and run laps with firing stages and penalty laps
+const Individual = "individual"
+
+// Runner is a competitor state machine of a race format, times are in milliseconds.
+// Methods return an error if the event isn't allowed in the current state
+type Runner interface {
+	SetStartTime(time int) error
+	OnLine() error
+	// Start reports whether the competitor started in time
+	Start(time int) (bool, error)
+	StartFiring(time, firingRange int) error
+	HitTarget(target int) error
+	QuitFiring(time int) (model.Shooting, error)
+	// FiringRange returns the firing range and whether the competitor is on it
+	FiringRange() (int, bool)
+	StartPenalty(time int) error
+	// QuitPenalty returns time spent on the penalty lap
+	QuitPenalty(time int) (int, error)
+	// FinishLap reports whether the competitor finished the race
+	FinishLap(time int) (bool, error)
+	QuitRunning(reason string) error
+	Lapped() error
+	AdjustTime(delta int, reason string) error
+	Disqualify(reason string) error
+
+	Status() model.Status
+	GetResult() model.Result
+
+	// State is saved to and restored from snapshots
+	json.Marshaler
+	json.Unmarshaler
+}
+
+var _ Runner = (*model.Runner)(nil)
+
+// Factory returns a new registered competitor of the format
+type Factory func(config model.Config, runnerID int) (Runner, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+func init() {
+	Register(Individual, func(config model.Config, runnerID int) (Runner, error) {
+		runner, err := model.NewRunner(config, runnerID)
+		if err != nil {
+			return nil, err
+		}
+		return runner, nil
+	})
+}
+
+// Register makes the race format available by name, it panics
+// if the name is empty or registered twice
+func Register(format string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if format == "" || factory == nil {
+		panic("rules: Register with empty format or nil factory")
+	}
+	if _, ok := factories[format]; ok {
+		panic("rules: Register called twice for format " + format)
+	}
+	factories[format] = factory
+}
+
+// Lookup returns Factory of the race format, empty format is Individual
+func Lookup(format string) (Factory, error) {
+	if format == "" {
+		format = Individual
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	factory, ok := factories[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+	return factory, nil
+}
+
+// Formats returns sorted names of registered race formats
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	formats := make([]string, 0, len(factories))
+	for format := range factories {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"errors"
+	"racingMetrics/internal/model"
+	"slices"
+	"testing"
+)
+
+// unregister removes the race format registered by a test
+func unregister(format string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(factories, format)
+}
+
+func TestLookup(t *testing.T) {
+	for _, format := range []string{"", Individual} {
+		factory, err := Lookup(format)
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", format, err)
+		}
+		runner, err := factory(model.Config{StartDelta: "00:01:30"}, 1)
+		if err != nil {
+			t.Fatalf("Factory of %q failed: %v", format, err)
+		}
+		if status := runner.Status(); status != model.StatusDNS {
+			t.Errorf("Expected new runner %s, got %s", model.StatusDNS, status)
+		}
+	}
+
+	if _, err := Lookup("relay"); !errors.Is(err, errUnknownFormat) {
+		t.Errorf("Expected %v, got %v", errUnknownFormat, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	factory, err := Lookup(Individual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Register("test_drill", factory)
+	t.Cleanup(func() { unregister("test_drill") })
+	if !slices.Contains(Formats(), "test_drill") {
+		t.Errorf("Expected test_drill in %v", Formats())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on registering a format twice")
+		}
+	}()
+	Register(Individual, factory)
+}
@@ -0,0 +1,308 @@
+// Package season aggregates classifications of races into season standings
+package season
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/classification"
+	"sort"
+	"strings"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errNoPoints         Err = "season points table is empty"
+	errNoRaces          Err = "season has no races"
+	errDropWorst        Err = "dropWorst must not be negative"
+	errNoClassification Err = "race has no such classification"
+)
+
+// Race is a race of the season
+type Race struct {
+	Name       string `json:"name"`
+	Discipline string `json:"discipline,omitempty"`
+	// Results is a JSON resulting table of the race, relative to the season config
+	Results string `json:"results"`
+}
+
+// Config is a season config. Points are awarded by race rank, the first one for the winner.
+// DropWorst worst results of every athlete, absences included, don't count in the overall standings
+type Config struct {
+	Points         []int  `json:"points"`
+	DropWorst      int    `json:"dropWorst,omitempty"`
+	Classification string `json:"classification,omitempty"`
+	Races          []Race `json:"races"`
+}
+
+// Athlete identifies a competitor across races by name and nation,
+// competitors without start list details are identified by ID
+type Athlete struct {
+	ID     int    `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Nation string `json:"nation,omitempty"`
+}
+
+func (a Athlete) String() string {
+	switch {
+	case a.Name == "":
+		return fmt.Sprintf("competitor(%d)", a.ID)
+	case a.Nation == "":
+		return a.Name
+	default:
+		return fmt.Sprintf("%s (%s)", a.Name, a.Nation)
+	}
+}
+
+// Row is a season standing of an athlete. Points, Ranks and Dropped are per race
+// in season order, Rank is 0 for races the athlete wasn't ranked in
+type Row struct {
+	Rank    int     `json:"rank"`
+	Athlete Athlete `json:"athlete"`
+	Total   int     `json:"total"`
+	Points  []int   `json:"points"`
+	Ranks   []int   `json:"ranks"`
+	Dropped []bool  `json:"dropped,omitempty"`
+}
+
+// Standings of the season or of a discipline
+type Standings struct {
+	Name  string   `json:"name"`
+	Races []string `json:"races"`
+	Rows  []Row    `json:"rows"`
+}
+
+// LoadConfig reads season config, race results paths are made relative to its directory
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	config := Config{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, err
+	}
+	if len(config.Points) == 0 {
+		return Config{}, errNoPoints
+	}
+	if len(config.Races) == 0 {
+		return Config{}, errNoRaces
+	}
+	if config.DropWorst < 0 {
+		return Config{}, errDropWorst
+	}
+	if config.Classification == "" {
+		config.Classification = classification.OverallName
+	}
+	for i, race := range config.Races {
+		if !filepath.IsAbs(race.Results) {
+			config.Races[i].Results = filepath.Join(filepath.Dir(path), race.Results)
+		}
+	}
+	return config, nil
+}
+
+// LoadResults reads JSON resulting tables of the races
+func LoadResults(config Config) ([]classification.Document, error) {
+	docs := make([]classification.Document, 0, len(config.Races))
+	for _, race := range config.Races {
+		data, err := os.ReadFile(race.Results)
+		if err != nil {
+			return nil, err
+		}
+		doc := classification.Document{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("race %s: %w", race.Name, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Compute returns overall standings followed by standings of every discipline in alphabetical order.
+// Worst results are dropped in the overall standings only
+func Compute(config Config, docs []classification.Document) ([]Standings, error) {
+	if len(docs) != len(config.Races) {
+		return nil, fmt.Errorf("%d results for %d races", len(docs), len(config.Races))
+	}
+	ranks := make([]map[Athlete]int, len(config.Races))
+	for i, race := range config.Races {
+		c, ok := findClassification(docs[i], config.Classification)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s of %s", errNoClassification, config.Classification, race.Name)
+		}
+		ranks[i] = make(map[Athlete]int, len(c.Standings))
+		for _, standing := range c.Standings {
+			ranks[i][AthleteOf(standing)] = standing.Rank
+		}
+	}
+
+	all := make([]int, len(config.Races))
+	disciplines := map[string][]int{}
+	for i, race := range config.Races {
+		all[i] = i
+		if race.Discipline != "" {
+			disciplines[race.Discipline] = append(disciplines[race.Discipline], i)
+		}
+	}
+	names := make([]string, 0, len(disciplines))
+	for name := range disciplines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	standings := []Standings{compute(classification.OverallName, config, ranks, all, config.DropWorst)}
+	for _, name := range names {
+		standings = append(standings, compute(name, config, ranks, disciplines[name], 0))
+	}
+	return standings, nil
+}
+
+func findClassification(doc classification.Document, name string) (classification.Classification, bool) {
+	for _, c := range doc.Classifications {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return classification.Classification{}, false
+}
+
+// AthleteOf returns the athlete of the standing
+func AthleteOf(standing classification.Standing) Athlete {
+	competitor := standing.Result.Competitor
+	if competitor.Name == "" {
+		return Athlete{ID: standing.Result.RunnerID}
+	}
+	return Athlete{Name: competitor.Name, Nation: competitor.Nation}
+}
+
+// compute returns standings of the races, dropping worst results of every athlete
+func compute(name string, config Config, ranks []map[Athlete]int, races []int, dropWorst int) Standings {
+	s := Standings{Name: name, Races: make([]string, 0, len(races))}
+	rows := map[Athlete]*Row{}
+	for _, race := range races {
+		s.Races = append(s.Races, config.Races[race].Name)
+		for athlete := range ranks[race] {
+			if rows[athlete] == nil {
+				rows[athlete] = &Row{
+					Athlete: athlete,
+					Points:  make([]int, len(races)),
+					Ranks:   make([]int, len(races)),
+				}
+			}
+		}
+	}
+	for _, row := range rows {
+		for i, race := range races {
+			rank := ranks[race][row.Athlete]
+			row.Ranks[i] = rank
+			if rank > 0 && rank <= len(config.Points) {
+				row.Points[i] = config.Points[rank-1]
+			}
+		}
+		row.Total = total(row, dropWorst)
+		s.Rows = append(s.Rows, *row)
+	}
+
+	sort.Slice(s.Rows, func(i, j int) bool {
+		if s.Rows[i].Total != s.Rows[j].Total {
+			return s.Rows[i].Total > s.Rows[j].Total
+		}
+		if c := comparePlacings(s.Rows[i].Ranks, s.Rows[j].Ranks); c != 0 {
+			return c < 0
+		}
+		return s.Rows[i].Athlete.String() < s.Rows[j].Athlete.String()
+	})
+	for i := range s.Rows {
+		s.Rows[i].Rank = i + 1
+		if i > 0 && s.Rows[i].Total == s.Rows[i-1].Total && comparePlacings(s.Rows[i].Ranks, s.Rows[i-1].Ranks) == 0 {
+			s.Rows[i].Rank = s.Rows[i-1].Rank
+		}
+	}
+	return s
+}
+
+// total sums points of the row without dropWorst lowest ones, marking them dropped
+func total(row *Row, dropWorst int) int {
+	order := make([]int, len(row.Points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return row.Points[order[i]] < row.Points[order[j]]
+	})
+	if dropWorst > 0 {
+		row.Dropped = make([]bool, len(row.Points))
+	}
+	sum := 0
+	for i, race := range order {
+		if i < dropWorst {
+			row.Dropped[race] = true
+			continue
+		}
+		sum += row.Points[race]
+	}
+	return sum
+}
+
+// comparePlacings breaks ties by the number of best placings: more wins first,
+// then more second places and so on. It returns -1 if a is better, 1 if b is
+func comparePlacings(a, b []int) int {
+	a, b = placings(a), placings(b)
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) > len(b):
+		return -1
+	case len(a) < len(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func placings(ranks []int) []int {
+	placings := make([]int, 0, len(ranks))
+	for _, rank := range ranks {
+		if rank > 0 {
+			placings = append(placings, rank)
+		}
+	}
+	sort.Ints(placings)
+	return placings
+}
+
+// Write writes standings as text: rank, athlete, total and points of every race, dropped ones in parentheses
+func Write(w io.Writer, standings []Standings) {
+	for _, s := range standings {
+		fmt.Fprintf(w, "Season standings %s\n", s.Name)
+		for i, race := range s.Races {
+			fmt.Fprintf(w, "Race %d: %s\n", i+1, race)
+		}
+		for _, row := range s.Rows {
+			points := make([]string, len(row.Points))
+			for i, p := range row.Points {
+				points[i] = fmt.Sprint(p)
+				if row.Dropped != nil && row.Dropped[i] {
+					points[i] = "(" + points[i] + ")"
+				}
+			}
+			fmt.Fprintf(w, "%d %s %d [%s]\n", row.Rank, row.Athlete, row.Total, strings.Join(points, " "))
+		}
+	}
+}
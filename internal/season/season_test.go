@@ -0,0 +1,163 @@
+package season
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+	"reflect"
+	"testing"
+)
+
+// raceDoc returns overall classification of named athletes by rank, 0 for unranked
+func raceDoc(ranks map[string]int) classification.Document {
+	c := classification.Classification{Name: classification.OverallName}
+	for name, rank := range ranks {
+		c.Standings = append(c.Standings, classification.Standing{
+			Rank:   rank,
+			Result: model.Result{Competitor: model.Competitor{Name: name, Nation: "NOR"}},
+		})
+	}
+	return classification.Document{Classifications: []classification.Classification{c}}
+}
+
+func TestCompute(t *testing.T) {
+	config := Config{
+		Points:         []int{100, 90, 80},
+		DropWorst:      1,
+		Classification: classification.OverallName,
+		Races: []Race{
+			{Name: "Sprint 1", Discipline: "sprint"},
+			{Name: "Pursuit 1", Discipline: "pursuit"},
+			{Name: "Sprint 2", Discipline: "sprint"},
+		},
+	}
+	docs := []classification.Document{
+		raceDoc(map[string]int{"A": 1, "B": 2, "C": 3, "D": 4}),
+		raceDoc(map[string]int{"A": 3, "B": 1, "C": 2}),
+		raceDoc(map[string]int{"B": 1, "C": 1, "D": 0}),
+	}
+	standings, err := Compute(config, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, s := range standings {
+		names = append(names, s.Name)
+	}
+	if want := []string{classification.OverallName, "pursuit", "sprint"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected standings %v, got %v", want, names)
+	}
+
+	// the worst result is dropped, absences included
+	overall := standings[0]
+	want := []Row{
+		{Rank: 1, Athlete: Athlete{Name: "B", Nation: "NOR"}, Total: 200, Points: []int{90, 100, 100}, Ranks: []int{2, 1, 1}, Dropped: []bool{true, false, false}},
+		{Rank: 2, Athlete: Athlete{Name: "C", Nation: "NOR"}, Total: 190, Points: []int{80, 90, 100}, Ranks: []int{3, 2, 1}, Dropped: []bool{true, false, false}},
+		{Rank: 3, Athlete: Athlete{Name: "A", Nation: "NOR"}, Total: 180, Points: []int{100, 80, 0}, Ranks: []int{1, 3, 0}, Dropped: []bool{false, false, true}},
+		{Rank: 4, Athlete: Athlete{Name: "D", Nation: "NOR"}, Total: 0, Points: []int{0, 0, 0}, Ranks: []int{4, 0, 0}, Dropped: []bool{true, false, false}},
+	}
+	if !reflect.DeepEqual(overall.Rows, want) {
+		t.Errorf("Expected overall\n%+v\ngot\n%+v", want, overall.Rows)
+	}
+
+	sprint := standings[2]
+	if sprint.Rows[0].Athlete.Name != "B" || sprint.Rows[0].Total != 190 || sprint.Rows[1].Athlete.Name != "C" {
+		t.Errorf("Expected B to lead sprint ahead of C, got %+v", sprint.Rows)
+	}
+	pursuit := standings[1]
+	if pursuit.Rows[0].Athlete.Name != "B" || pursuit.Rows[0].Dropped != nil {
+		t.Errorf("Expected B to lead pursuit without drops, got %+v", pursuit.Rows[0])
+	}
+}
+
+func TestComputeTie(t *testing.T) {
+	config := Config{Points: []int{100, 90}, Classification: classification.OverallName, Races: []Race{{Name: "1"}, {Name: "2"}}}
+	docs := []classification.Document{
+		raceDoc(map[string]int{"A": 1, "B": 2}),
+		raceDoc(map[string]int{"B": 1, "A": 2}),
+	}
+	standings, err := Compute(config, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := standings[0].Rows
+	if rows[0].Rank != 1 || rows[1].Rank != 1 || rows[0].Athlete.Name != "A" {
+		t.Errorf("Expected A and B sharing the first place, got %+v", rows)
+	}
+
+	config.Classification = "W-JUN"
+	if _, err := Compute(config, docs); !errors.Is(err, errNoClassification) {
+		t.Errorf("Expected %v, got %v", errNoClassification, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"sunny_5_skiers", "jury"} {
+		data, err := os.ReadFile(filepath.Join("..", "service", "testdata", name, "table.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "table.json"), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "season.json")
+	config := `{"points": [100, 90, 80, 70, 60], "races": [
+		{"name": "Sunny", "results": "sunny_5_skiers/table.json"},
+		{"name": "Jury", "results": "jury/table.json"}
+	]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	season, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := LoadResults(season)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standings, err := Compute(season, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 1 || len(standings[0].Rows) == 0 || standings[0].Rows[0].Athlete.ID == 0 {
+		t.Errorf("Expected overall standings of competitors identified by ID, got %+v", standings)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"points": [], "races": [{"name": "1"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); !errors.Is(err, errNoPoints) {
+		t.Errorf("Expected %v, got %v", errNoPoints, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var b bytes.Buffer
+	Write(&b, []Standings{{
+		Name:  classification.OverallName,
+		Races: []string{"Sprint 1", "Sprint 2"},
+		Rows: []Row{
+			{Rank: 1, Athlete: Athlete{Name: "A", Nation: "NOR"}, Total: 100, Points: []int{100, 0}, Dropped: []bool{false, true}},
+			{Rank: 2, Athlete: Athlete{ID: 7}, Total: 90, Points: []int{0, 90}, Dropped: []bool{true, false}},
+		},
+	}})
+	want := `Season standings Overall
+Race 1: Sprint 1
+Race 2: Sprint 2
+1 A (NOR) 100 [100 (0)]
+2 competitor(7) 90 [(0) 90]
+`
+	if b.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, b.String())
+	}
+}
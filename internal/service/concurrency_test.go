@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func newTestEngine(t *testing.T) *EventLogger {
+	t.Helper()
+	s := NewRunLog(filepath.Join("testdata", "sunny_5_skiers", "config.json"), os.DevNull, log.New(io.Discard, "", 0))
+	s.SetSink(outgoing.Discard)
+	return s
+}
+
+// competitorEvents returns a full race of the competitor, firing on its own range
+func competitorEvents(t *testing.T, runnerID, firingRange int) []event.Event {
+	t.Helper()
+	start := 10*3600*1000 + runnerID%1000*30*1000
+	lines := []string{
+		fmt.Sprintf("[09:00:00.000] 1 %d", runnerID),
+		fmt.Sprintf("[09:10:00.000] 2 %d %s", runnerID, model.RawTiming.Format(start)),
+		fmt.Sprintf("[09:50:00.000] 3 %d", runnerID),
+		fmt.Sprintf("[%s] 4 %d", model.RawTiming.Format(start+1000), runnerID),
+		fmt.Sprintf("[%s] 10 %d", model.RawTiming.Format(start+600000), runnerID),
+		fmt.Sprintf("[%s] 5 %d %d", model.RawTiming.Format(start+610000), runnerID, firingRange),
+		fmt.Sprintf("[%s] 6 %d 1", model.RawTiming.Format(start+615000), runnerID),
+		fmt.Sprintf("[%s] 7 %d", model.RawTiming.Format(start+640000), runnerID),
+		fmt.Sprintf("[%s] 10 %d", model.RawTiming.Format(start+1200000), runnerID),
+	}
+	events := make([]event.Event, 0, len(lines))
+	for _, line := range lines {
+		e, err := event.ParseEvent(line, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestConcurrentSubmit(t *testing.T) {
+	const feeds, perFeed = 4, 25
+	s := newTestEngine(t)
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+		mu   sync.Mutex
+		seqs = make(map[int]bool)
+	)
+	for feed := 1; feed <= feeds; feed++ {
+		var events []event.Event
+		for i := range perFeed {
+			events = append(events, competitorEvents(t, feed*1000+i, feed)...)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, e := range events {
+				seq, err := s.Submit(e)
+				if err != nil {
+					t.Errorf("Submit %s failed: %v", e, err)
+					return
+				}
+				mu.Lock()
+				seqs[seq] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	// queries while ingesting
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			s.Standings()
+			s.Results()
+			s.Result(1000)
+		}
+	}()
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	expectedEvents := feeds * perFeed * len(competitorEvents(t, 1, 1))
+	if len(seqs) != expectedEvents {
+		t.Errorf("Expected %d distinct event numbers, got %d", expectedEvents, len(seqs))
+	}
+	results := s.Results()
+	if len(results) != feeds*perFeed {
+		t.Fatalf("Expected %d results, got %d", feeds*perFeed, len(results))
+	}
+	for _, result := range results {
+		if result.Status != model.StatusFinished || result.Hits != 1 {
+			t.Errorf("Expected competitor(%d) finished with 1 hit, got %s with %d", result.RunnerID, result.Status, result.Hits)
+		}
+	}
+}
+
+func TestSubmitRejected(t *testing.T) {
+	s := newTestEngine(t)
+	events := competitorEvents(t, 1, 1)
+	for _, e := range events[:4] {
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, _ := s.Result(1)
+
+	rejected := []string{
+		"[10:05:00.000] 4 1",    // started twice
+		"[10:05:00.000] 4 2",    // not registered
+		"[10:05:00.000] 14 1 1", // voiding registration invalidates the later events
+	}
+	for _, line := range rejected {
+		e, err := event.ParseEvent(line, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Submit(e); err == nil {
+			t.Errorf("Expected %s to be rejected", line)
+		}
+	}
+
+	if after, _ := s.Result(1); !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected result %+v after rejected events, got %+v", before, after)
+	}
+	seq, err := s.Submit(events[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq != 5 {
+		t.Errorf("Expected rejected events not to be numbered, got event %d", seq)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	dir := filepath.Join("testdata", "sunny_5_skiers")
+	s := NewRunLog(filepath.Join(dir, "config.json"), os.DevNull, log.New(io.Discard, "", 0))
+	eventLog, table := &bytes.Buffer{}, &bytes.Buffer{}
+	s.SetOutput(eventLog, table)
+	s.SetSink(outgoing.NewTextSink(eventLog))
+
+	// the start, the firing range and the finish send events of every competitor
+	file, err := os.Open(filepath.Join(dir, "events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	decoder, err := event.NewDecoder(file, event.FormatAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feedEvents := make([][]event.Event, 3)
+	for {
+		e, err := decoder.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case e.Type <= event.Start:
+			feedEvents[0] = append(feedEvents[0], e)
+		case e.Type < event.EndMainLap:
+			feedEvents[1] = append(feedEvents[1], e)
+		default:
+			feedEvents[2] = append(feedEvents[2], e)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, events := range feedEvents {
+		feed := s.NewFeed()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, e := range events {
+				if err := feed.Submit(e); err != nil {
+					t.Errorf("Submit %s failed: %v", e, err)
+				}
+			}
+			if err := feed.Close(); err != nil {
+				t.Errorf("Close failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := s.PrintResultingTable(); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, filepath.Join(dir, "output.log"), eventLog.Bytes())
+	compareGolden(t, filepath.Join(dir, "table.txt"), table.Bytes())
+}
+
+func TestFeedOrder(t *testing.T) {
+	s := newTestEngine(t)
+	events := competitorEvents(t, 1, 1)
+	start, firingRange, finish := s.NewFeed(), s.NewFeed(), s.NewFeed()
+
+	// events of the range and the finish wait for the start
+	for _, e := range []event.Event{events[4], events[8]} {
+		if err := finish.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range events[5:8] {
+		if err := firingRange.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := firingRange.Submit(events[5]); !errors.Is(err, errFeedOrder) {
+		t.Errorf("Expected %v, got %v", errFeedOrder, err)
+	}
+	if _, ok := s.Result(1); ok {
+		t.Fatal("Expected no events applied before the start feed")
+	}
+	for _, e := range events[:4] {
+		if err := start.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the closed start feed no longer holds back the others
+	if err := start.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := start.Submit(events[0]); !errors.Is(err, errFeedClosed) {
+		t.Errorf("Expected %v, got %v", errFeedClosed, err)
+	}
+	if err := errors.Join(firingRange.Close(), finish.Close()); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := s.Result(1); result.Status != model.StatusFinished || result.Hits != 1 {
+		t.Errorf("Expected competitor(1) finished with 1 hit, got %s with %d", result.Status, result.Hits)
+	}
+	if s.seq != len(events) || len(s.feeds) != 0 {
+		t.Errorf("Expected %d events applied and feeds dropped, got %d and %d feeds", len(events), s.seq, len(s.feeds))
+	}
+}
+
+func TestFeedRejected(t *testing.T) {
+	s := newTestEngine(t)
+	events := competitorEvents(t, 1, 1)
+	feed := s.NewFeed()
+	for _, e := range events[:4] {
+		if err := feed.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// started twice
+	if err := feed.Submit(events[3]); err == nil {
+		t.Error("Expected the second start to be rejected")
+	}
+	if err := feed.Close(); err != nil {
+		t.Errorf("Expected the rejection reported once, got %v", err)
+	}
+	if s.seq != 4 {
+		t.Errorf("Expected rejected event not to be numbered, got event %d", s.seq)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/rules"
+	"sort"
+	"strings"
+)
+
+// historyEntry is an accepted event which corrections can refer to
+type historyEntry struct {
+	Event  event.Event `json:"event"`
+	Voided bool        `json:"voided,omitempty"`
+}
+
+// correction is an audit record of a jury decision
+type correction struct {
+	Time     string     `json:"time"`
+	EventID  event.Type `json:"eventID"`
+	RunnerID int        `json:"runnerID"`
+	RefSeq   int        `json:"refSeq"`
+	OldValue string     `json:"oldValue,omitempty"`
+	NewValue string     `json:"newValue,omitempty"`
+}
+
+var correctionKinds = map[event.Type]outgoing.Kind{
+	event.AmendTime:  outgoing.TimeAmended,
+	event.AmendParam: outgoing.ParamAmended,
+	event.Void:       outgoing.Voided,
+}
+
+func (c correction) String() string {
+	switch c.EventID {
+	case event.AmendTime:
+		return fmt.Sprintf("event(%d) of competitor(%d): time %s -> %s", c.RefSeq, c.RunnerID, c.OldValue, c.NewValue)
+	case event.AmendParam:
+		return fmt.Sprintf("event(%d) of competitor(%d): parameter %s -> %s", c.RefSeq, c.RunnerID, c.OldValue, c.NewValue)
+	default:
+		return fmt.Sprintf("event(%d) of competitor(%d): voided", c.RefSeq, c.RunnerID)
+	}
+}
+
+// applyEvent applies the event, recording it for later corrections
+func (s *EventLogger) applyEvent(e event.Event) error {
+	if e.Type.IsCorrection() {
+		return s.handleCorrection(e)
+	}
+	if err := s.handleEvent(e); err != nil {
+		return err
+	}
+	s.history = append(s.history, historyEntry{Event: e})
+	return nil
+}
+
+func (s *EventLogger) handleCorrection(e event.Event) error {
+	entry := s.findHistoryEntry(e.RefSeq)
+	if entry == nil || entry.Voided {
+		return fmt.Errorf("no such event to correct: %d", e.RefSeq)
+	}
+	if entry.Event.Competitor != e.Competitor {
+		return fmt.Errorf("event %d doesn't belong to competitor(%d)", e.RefSeq, e.Competitor)
+	}
+
+	c := correction{
+		Time:     model.RawTiming.Format(e.Time),
+		EventID:  e.Type,
+		RunnerID: e.Competitor,
+		RefSeq:   e.RefSeq,
+	}
+	original := *entry
+	amended := entry.Event
+	switch e.Type {
+	case event.AmendTime:
+		if err := s.checkOrder(entry, e.NewTime); err != nil {
+			return err
+		}
+		c.OldValue = model.RawTiming.Format(entry.Event.Time)
+		c.NewValue = model.RawTiming.Format(e.NewTime)
+		amended.Time = e.NewTime
+	case event.AmendParam:
+		params := entry.Event.Params()
+		if len(params) == 0 {
+			return fmt.Errorf("event %d has no parameter", e.RefSeq)
+		}
+		if err := amended.SetParams(e.NewParams); err != nil {
+			return err
+		}
+		c.OldValue = strings.Join(params, " ")
+		c.NewValue = strings.Join(amended.Params(), " ")
+	case event.Void:
+		entry.Voided = true
+	}
+	entry.Event = amended
+
+	status := s.status(e.Competitor)
+	if err := s.rebuild(); err != nil {
+		// the history was valid before the correction
+		*entry = original
+		if rerr := s.rebuild(); rerr != nil {
+			s.failure = fmt.Errorf("restoring state after rejected correction: %w", rerr)
+			return s.failure
+		}
+		return fmt.Errorf("corrected history is invalid: %w", err)
+	}
+	s.corrections = append(s.corrections, c)
+
+	s.emit(e, outgoing.Event{
+		Kind:     correctionKinds[e.Type],
+		RefSeq:   c.RefSeq,
+		OldValue: c.OldValue,
+		NewValue: c.NewValue,
+	})
+	s.notifyStatus(e, status)
+	return nil
+}
+
+func (s *EventLogger) findHistoryEntry(seq int) *historyEntry {
+	i := sort.Search(len(s.history), func(i int) bool {
+		return s.history[i].Event.Seq >= seq
+	})
+	if i == len(s.history) || s.history[i].Event.Seq != seq {
+		return nil
+	}
+	return &s.history[i]
+}
+
+// checkOrder returns error if the entry amended to the time would break the order of events of its competitor
+func (s *EventLogger) checkOrder(entry *historyEntry, time int) error {
+	for _, other := range s.history {
+		if other.Voided || other.Event.Competitor != entry.Event.Competitor || other.Event.Seq == entry.Event.Seq {
+			continue
+		}
+		if (other.Event.Seq < entry.Event.Seq && other.Event.Time > time) ||
+			(other.Event.Seq > entry.Event.Seq && other.Event.Time < time) {
+			return fmt.Errorf("amended event %d would be out of order with event %d at %s",
+				entry.Event.Seq, other.Event.Seq, model.RawTiming.Format(other.Event.Time))
+		}
+	}
+	return nil
+}
+
+// rebuild recomputes state from the corrected history without emitting it
+func (s *EventLogger) rebuild() error {
+	defer s.silence()()
+
+	s.runners = make(map[int]rules.Runner)
+	s.firingRanges = make(map[int]rangeStatus)
+	for _, entry := range s.history {
+		if entry.Voided {
+			continue
+		}
+		if err := s.handleEvent(entry.Event); err != nil {
+			return fmt.Errorf("event %d at %s: %w", entry.Event.Seq, model.RawTiming.Format(entry.Event.Time), err)
+		}
+	}
+	return nil
+}
+
+func (s *EventLogger) printCorrections(w io.Writer) {
+	if len(s.corrections) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Jury corrections")
+	for _, c := range s.corrections {
+		fmt.Fprintf(w, "[%s] %s\n", c.Time, c)
+	}
+}
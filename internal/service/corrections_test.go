@@ -0,0 +1,94 @@
+package service
+
+import (
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"reflect"
+	"testing"
+)
+
+// correctedEngine returns engine with a full race of competitor(1)
+func correctedEngine(t *testing.T) *EventLogger {
+	t.Helper()
+	s := newTestEngine(t)
+	for _, e := range competitorEvents(t, 1, 1) {
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func submitLine(t *testing.T, s *EventLogger, line string) error {
+	t.Helper()
+	e, err := event.ParseEvent(line, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Submit(e)
+	return err
+}
+
+func TestCorrections(t *testing.T) {
+	cases := map[string]struct {
+		line  string
+		check func(model.Result) bool
+	}{
+		"amend time": {"[11:00:00.000] 12 1 9 10:20:40.000", func(r model.Result) bool {
+			return r.TotalTime == 20*60*1000+10000
+		}},
+		"amend parameter": {"[11:00:00.000] 13 1 6 2", func(r model.Result) bool {
+			return r.Shootings[0].FiringRange == 2
+		}},
+		"void": {"[11:00:00.000] 14 1 7", func(r model.Result) bool {
+			return r.Hits == 0 && r.Shootings[0].Hits == 0
+		}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := correctedEngine(t)
+			if err := submitLine(t, s, c.line); err != nil {
+				t.Fatal(err)
+			}
+			result, _ := s.Result(1)
+			if result.Status != model.StatusFinished || !c.check(result) {
+				t.Errorf("Unexpected corrected result %+v", result)
+			}
+			if len(s.corrections) != 1 {
+				t.Errorf("Expected the correction to be recorded, got %+v", s.corrections)
+			}
+		})
+	}
+}
+
+func TestCorrectionRejected(t *testing.T) {
+	s := correctedEngine(t)
+	before, _ := s.Result(1)
+
+	rejected := []string{
+		"[11:00:00.000] 14 1 4",              // the runner would fire without a start
+		"[11:00:00.000] 12 1 5 10:30:00.000", // the first lap would end after the second one
+		"[11:00:00.000] 14 1 42",             // no such event
+		"[11:00:00.000] 14 2 7",              // event of another competitor
+		"[11:00:00.000] 13 1 3 1",            // event without parameters
+	}
+	for _, line := range rejected {
+		if err := submitLine(t, s, line); err == nil {
+			t.Errorf("Expected %s to be rejected", line)
+		}
+	}
+	if after, _ := s.Result(1); !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected result %+v after rejected corrections, got %+v", before, after)
+	}
+	if len(s.corrections) != 0 {
+		t.Errorf("Expected no corrections, got %+v", s.corrections)
+	}
+
+	// the restored history is still correctable
+	if err := submitLine(t, s, "[11:00:00.000] 14 1 7"); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := s.Result(1); after.Hits != 0 {
+		t.Errorf("Expected the hit voided, got %d hits", after.Hits)
+	}
+}
@@ -2,60 +2,35 @@
 package service
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/journal"
 	"racingMetrics/internal/model"
-	"sort"
-	"strconv"
-	"strings"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/rules"
+	"sync"
 )
 
 type rangeStatus int
 
-const (
-	registerRunner int = iota + 1
-	setRunnerTime
-	runnerOnStart
-	startRunner
-	runnerStartFire
-	runnerHitTarget
-	runnerQuitFire
-	runnerEnterPenalty
-	runnerLeftPenalty
-	runnerEndMain
-	runnerCantRun
-)
 const (
 	rangeFree     rangeStatus = iota
 	rangeOccupied rangeStatus = 1
 )
-const (
-	timeInd = iota
-	eventIDInd
-	runnerIDInd
-	extraParamInd
-)
 
 const totalTargetsAmount = 5
 
-type runnerInterface interface {
-	SetStartTime(time string) error
-	OnLine() error
-	Start(time string) (bool, error)
-	StartFiring(firingRange int) error
-	HitTarget(target int) error
-	QuitFiring() (int, error)
-	StartPenalty(time string) error
-	QuitPenalty(time string) error
-	FinishLap(time string) (bool, error)
-	QuitRunning() error
+// ErrUndelivered wraps sink errors of an accepted event, unlike rejection the event changes the state
+const ErrUndelivered Err = "event is accepted, but its outgoing events are not delivered"
 
-	GetResult() (int, string)
-}
+// ErrUnrecorded wraps journal and snapshot errors of an accepted event, the event changes the state
+const ErrUnrecorded Err = "event is accepted, but it is not recorded"
 
 // NewRunLog returns EventLogger
 func NewRunLog(jsonConfigPath, eventsPath string, logger *log.Logger) *EventLogger {
@@ -63,290 +38,563 @@ func NewRunLog(jsonConfigPath, eventsPath string, logger *log.Logger) *EventLogg
 	if logger == nil {
 		log.Fatalf("NewRunLog looger is nil")
 	}
+	s, err := NewEventLogger(config, logger)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	s.eventsPath = eventsPath
+	return s
+}
+
+// NewEventLogger returns EventLogger of the config loaded by LoadConfig
+// taking events by Submit instead of an events file
+func NewEventLogger(config model.Config, logger *log.Logger) (*EventLogger, error) {
+	newRunner, err := rules.Lookup(config.Format)
+	if err != nil {
+		return nil, err
+	}
 	return &EventLogger{
 		logger:       logger,
-		eventsPath:   eventsPath,
+		sink:         outgoing.NewTextSink(os.Stdout),
+		table:        os.Stdout,
 		config:       config,
-		runners:      make(map[int]runnerInterface),
+		newRunner:    newRunner,
+		runners:      make(map[int]rules.Runner),
 		firingRanges: make(map[int]rangeStatus),
-	}
+	}, nil
 }
 
-// EventLogger logs incoming events
+// EventLogger logs incoming events. Applying events and queries are safe for concurrent use,
+// setters are to be called before running events
 type EventLogger struct {
+	mu           sync.RWMutex
 	logger       *log.Logger
+	sink         outgoing.Sink
+	table        io.Writer
 	eventsPath   string
 	config       model.Config
-	runners      map[int]runnerInterface
+	newRunner    rules.Factory
+	runners      map[int]rules.Runner
 	firingRanges map[int]rangeStatus
+	seq          int
+	history      []historyEntry
+	corrections  []correction
+	journal      *journal.Journal
+	snapshots    *snapshotter
+	startList    map[int]model.Competitor
+	format       Format
+	inputFormat  event.Format
+	hooks        []Hooks
+	feeds        []*Feed
+	released     *sync.Cond
+	restored     bool
+	failure      error
+	outbox       []outgoing.Event
+	silent       bool
+}
+
+// SetStartList joins competitor details to results and rejects
+// registration of competitors missing from the start list
+func (s *EventLogger) SetStartList(competitors []model.Competitor) {
+	s.startList = make(map[int]model.Competitor, len(competitors))
+	for _, competitor := range competitors {
+		s.startList[competitor.ID] = competitor
+	}
+}
+
+// SetOutput sets writers of the text event log and of the resulting table
+func (s *EventLogger) SetOutput(log, table io.Writer) {
+	s.sink = outgoing.NewTextSink(log)
+	s.table = table
+}
+
+// SetSink sets the sink of outgoing events, they are written to stdout as text by default
+func (s *EventLogger) SetSink(sink outgoing.Sink) {
+	s.sink = sink
+}
+
+// SetInputFormat sets format of the events file, it's detected by default
+func (s *EventLogger) SetInputFormat(format event.Format) error {
+	if !format.Valid() {
+		return fmt.Errorf("%w: %s", errUnknownInputFormat, format)
+	}
+	s.inputFormat = format
+	return nil
+}
+
+// SetEventsPath sets the events file run by RunEvents
+func (s *EventLogger) SetEventsPath(path string) {
+	s.eventsPath = path
+}
+
+// SetJournal makes EventLogger restore its state from the journal before
+// the first applied event and append every accepted event to it
+func (s *EventLogger) SetJournal(j *journal.Journal) {
+	s.journal = j
 }
 
 func parseConfig(jsonConfigPath string) model.Config {
+	config, err := LoadConfig(jsonConfigPath)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	return config
+}
+
+// LoadConfig reads run config from JSON file and fills defaults
+func LoadConfig(jsonConfigPath string) (model.Config, error) {
 	jsonData, err := os.ReadFile(jsonConfigPath)
 	if err != nil {
-		log.Fatalf("Error reading JSON file: %v", err)
+		return model.Config{}, err
 	}
 
 	config := model.Config{}
 	err = json.Unmarshal(jsonData, &config)
 	if err != nil {
-		log.Fatalf("Error unmarshalling JSON: %v", err)
+		return model.Config{}, err
+	}
+
+	if !config.Precision.Valid() {
+		return model.Config{}, fmt.Errorf("unknown precision: %s", config.Precision)
+	}
+	if config.Precision == "" {
+		config.Precision = model.DefaultPrecision
+	}
+	if !config.Rounding.Valid() {
+		return model.Config{}, fmt.Errorf("unknown rounding: %s", config.Rounding)
+	}
+	if config.Rounding == "" {
+		config.Rounding = model.RoundingTruncate
+	}
+	if _, err := rules.Lookup(config.Format); err != nil {
+		return model.Config{}, err
 	}
 
 	config.TargetsAmount = totalTargetsAmount
-	return config
+	return config, nil
 }
 
-// RunEvents runs given events
-func (s *EventLogger) RunEvents(ctx context.Context) {
+// RunEvents runs given events, it stops at the first rejected one
+func (s *EventLogger) RunEvents(ctx context.Context) (err error) {
 	file, err := os.Open(s.eventsPath)
 	if err != nil {
-		log.Fatalf("Error opening text file: %v", err)
+		return err
 	}
 	defer func() {
-		err := file.Close()
-		if err != nil {
-			log.Fatalf("Error closing text file: %v", err)
+		err = errors.Join(err, file.Close())
+	}()
+
+	s.mu.Lock()
+	err = s.restoreOnce()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		s.mu.Lock()
+		if serr := s.takeSnapshot(); serr != nil {
+			err = errors.Join(err, fmt.Errorf("taking snapshot: %w", serr))
 		}
+		s.mu.Unlock()
 	}()
 
-	scanner := bufio.NewScanner(file)
+	reader, err := event.NewDecoder(file, s.inputFormat)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
 
-	for scanner.Scan() {
+	for {
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		default:
-			line := scanner.Text()
-			args := strings.Fields(line)
-			s.parseEvent(args)
+		}
+		e, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading events: %w", err)
+		}
+		if err := s.Apply(e); err != nil {
+			return err
 		}
 	}
 }
 
-// PrintResultingTable -
-func (s *EventLogger) PrintResultingTable() {
-	type Log struct {
-		time int
-		log  string
+// restoreOnce restores state from the snapshot and the journal before the first
+// applied event, whether it comes from the events file, Submit or a feed.
+// A failed restore or journal write leaves the engine unusable, the error is returned every time
+func (s *EventLogger) restoreOnce() error {
+	if !s.restored {
+		s.restored = true
+		s.failure = s.restore()
 	}
-	successfulRunners := []Log{}
-	failedRunners := []Log{}
+	return s.failure
+}
 
-	for _, runner := range s.runners {
-		result := Log{}
-		result.time, result.log = runner.GetResult()
-		if result.time == 0 {
-			failedRunners = append(failedRunners, result)
-		} else {
-			successfulRunners = append(successfulRunners, result)
-		}
+// restore restores state from the snapshot and the journal
+func (s *EventLogger) restore() error {
+	if err := s.restoreSnapshot(); err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
 	}
+	if err := s.replayJournal(); err != nil {
+		return fmt.Errorf("replaying journal: %w", err)
+	}
+	return nil
+}
 
-	sort.Slice(successfulRunners, func(i, j int) bool {
-		return successfulRunners[i].time < successfulRunners[j].time
-	})
-
-	fmt.Println("Resulting table")
+// Apply applies the next event of the events file, events already applied
+// or restored from the journal or the snapshot are skipped.
+// A rejected event doesn't change the state, an error wrapping ErrUndelivered
+// or ErrUnrecorded is returned for an accepted event the sink or the journal failed on
+func (s *EventLogger) Apply(e event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.restoreOnce(); err != nil {
+		return err
+	}
+	if e.Seq <= s.seq {
+		return nil
+	}
+	if err := s.process(e); err != nil {
+		return fmt.Errorf("event %d at %s: %w", e.Seq, model.RawTiming.Format(e.Time), err)
+	}
+	return nil
+}
 
-	for _, result := range successfulRunners {
-		fmt.Println(result.log)
+// Submit applies an event of a single live source numbering it after the last accepted one
+// and returns its number. Events are applied one at a time in the order of calls,
+// sources sending events of a competitor concurrently are to use feeds to keep them in order.
+// A rejected event isn't numbered and doesn't change the state. An accepted event
+// the sink or the journal failed on is numbered, the error wraps ErrUndelivered or ErrUnrecorded then
+func (s *EventLogger) Submit(e event.Event) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.restoreOnce(); err != nil {
+		return 0, err
+	}
+	e.Seq = s.seq + 1
+	err := s.process(e)
+	if err != nil && !errors.Is(err, ErrUndelivered) && !errors.Is(err, ErrUnrecorded) {
+		return 0, err
 	}
+	return e.Seq, err
+}
 
-	for _, result := range failedRunners {
-		fmt.Println(result.log)
+// process applies and accepts the event, its outgoing events are delivered
+// only after the state is changed, so sink and journal errors don't reject it
+func (s *EventLogger) process(e event.Event) error {
+	if err := s.applyEvent(e); err != nil {
+		s.outbox = s.outbox[:0]
+		return err
 	}
+	return errors.Join(s.accept(e), s.deliver())
 }
 
-func (s *EventLogger) parseEvent(args []string) {
-	time := args[timeInd][1 : len(args[timeInd])-1]
-	eventID, err := strconv.Atoi(args[eventIDInd])
-	if err != nil {
-		s.logger.Fatalf("Error parsing event ID: %v", err)
+// accept records the applied event to the journal and snapshots. The journal
+// missing an accepted event can't be replayed any more, so the engine stops then
+func (s *EventLogger) accept(e event.Event) error {
+	s.seq = e.Seq
+	if err := s.appendJournal(e.String()); err != nil {
+		s.failure = fmt.Errorf("%w: writing journal: %w", ErrUnrecorded, err)
+		return s.failure
 	}
-	runnerID, err := strconv.Atoi(args[runnerIDInd])
-	if err != nil {
-		s.logger.Fatalf("Error parsing event ID: %v", err)
-	}
-
-	switch eventID {
-	case registerRunner:
-		s.handleRegisterRunner(time, runnerID)
-	case setRunnerTime:
-		s.handleSetRunnerTime(time, runnerID, args[extraParamInd])
-	case runnerOnStart:
-		s.handleRunnerOnStart(time, runnerID)
-	case startRunner:
-		s.handleStartRunner(time, runnerID)
-	case runnerStartFire:
-		s.handleRunnerStartFire(time, runnerID, args[extraParamInd])
-	case runnerHitTarget:
-		s.handleRunnerHitTarget(time, runnerID, args[extraParamInd])
-	case runnerQuitFire:
-		s.handleRunnerQuitFire(time, runnerID)
-	case runnerEnterPenalty:
-		s.handleRunnerEnterPenalty(time, runnerID)
-	case runnerLeftPenalty:
-		s.handleRunnerLeftPenalty(time, runnerID)
-	case runnerEndMain:
-		s.handleRunnerEndMain(time, runnerID)
-	case runnerCantRun:
-		s.handleRunnerCantRun(time, runnerID, args[extraParamInd])
-	default:
-		fmt.Printf("[%s] No such event for competitor(%d)\n", time, runnerID)
+	if err := s.snapshotIfDue(); err != nil {
+		return fmt.Errorf("%w: taking snapshot: %w", ErrUnrecorded, err)
 	}
+	return nil
 }
 
-func (s *EventLogger) handleRegisterRunner(time string, runnerID int) {
-	if _, ok := s.runners[runnerID]; ok {
-		s.logger.Fatalf("Can't register same competitor twice: %d", runnerID)
+// replayJournal rebuilds state from journaled events not covered
+// by the snapshot without printing them
+func (s *EventLogger) replayJournal() error {
+	if s.journal == nil {
+		return nil
 	}
-	runner, err := model.NewRunner(
-		s.config,
-		runnerID)
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+	if s.journal.Seq() < s.seq {
+		return fmt.Errorf("journal ends at event %d, before snapshot event %d", s.journal.Seq(), s.seq)
 	}
 
-	s.runners[runnerID] = runner
+	defer s.silence()()
 
-	fmt.Printf("[%s] The competitor(%d) registered\n", time, runnerID)
+	return s.journal.Replay(func(rec journal.Record) error {
+		if rec.Seq <= s.seq {
+			return nil
+		}
+		e, err := event.ParseEvent(rec.Line, rec.Seq)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", rec.Seq, err)
+		}
+		if err := s.applyEvent(e); err != nil {
+			return fmt.Errorf("record %d: %w", rec.Seq, err)
+		}
+		s.seq = rec.Seq
+		return nil
+	})
 }
 
-func (s *EventLogger) handleSetRunnerTime(time string, runnerID int, drawTime string) {
-	s.checkRunnerExist(runnerID)
-
-	err := s.runners[runnerID].SetStartTime(drawTime)
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+func (s *EventLogger) appendJournal(line string) error {
+	if s.journal == nil {
+		return nil
 	}
+	return s.journal.Append(line)
+}
 
-	fmt.Printf("[%s] The start time for the competitor(%d) was set by a draw to %s\n", time, runnerID, drawTime)
+func (s *EventLogger) handleEvent(e event.Event) error {
+	status := s.status(e.Competitor)
+	if err := s.dispatchEvent(e); err != nil {
+		return err
+	}
+	s.notifyStatus(e, status)
+	return nil
 }
 
-func (s *EventLogger) handleRunnerOnStart(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
+func (s *EventLogger) dispatchEvent(e event.Event) error {
+	if e.Type != event.Register {
+		if _, ok := s.runners[e.Competitor]; !ok {
+			return fmt.Errorf("no such competitor registered: %d", e.Competitor)
+		}
+	}
+	if s.ignored(e) {
+		return nil
+	}
 
-	err := s.runners[runnerID].OnLine()
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+	switch e.Type {
+	case event.Register:
+		return s.handleRegisterRunner(e)
+	case event.SetStartTime:
+		return s.handleSetRunnerTime(e)
+	case event.OnStartLine:
+		return s.handleRunnerOnStart(e)
+	case event.Start:
+		return s.handleStartRunner(e)
+	case event.OnFiringRange:
+		return s.handleRunnerStartFire(e)
+	case event.HitTarget:
+		return s.handleRunnerHitTarget(e)
+	case event.LeftFiringRange:
+		return s.handleRunnerQuitFire(e)
+	case event.EnterPenalty:
+		return s.handleRunnerEnterPenalty(e)
+	case event.LeftPenalty:
+		return s.handleRunnerLeftPenalty(e)
+	case event.EndMainLap:
+		return s.handleRunnerEndMain(e)
+	case event.CantContinue:
+		return s.handleRunnerCantRun(e)
+	case event.AdjustTime:
+		return s.handleAdjustRunnerTime(e)
+	case event.Disqualify:
+		return s.handleDisqualifyRunner(e)
+	case event.Lapped:
+		return s.handleLapRunner(e)
+	default:
+		s.emit(e, outgoing.Event{Kind: outgoing.UnknownEvent})
+		return nil
 	}
-	fmt.Printf("[%s] The competitor(%d) is on the start line\n", time, runnerID)
 }
 
-func (s *EventLogger) handleStartRunner(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
+// ignored reports whether the event is a course event of a disqualified competitor,
+// such events may still be on the feed when the jury decides
+func (s *EventLogger) ignored(e event.Event) bool {
+	switch e.Type {
+	case event.Register, event.AdjustTime, event.Disqualify:
+		return false
+	}
+	return s.runners[e.Competitor].Status() == model.StatusDSQ
+}
 
-	started, err := s.runners[runnerID].Start(time)
+func (s *EventLogger) handleRegisterRunner(e event.Event) error {
+	if _, ok := s.runners[e.Competitor]; ok {
+		return fmt.Errorf("can't register same competitor twice: %d", e.Competitor)
+	}
+	if _, ok := s.startList[e.Competitor]; s.startList != nil && !ok {
+		return fmt.Errorf("competitor(%d) is not in the start list", e.Competitor)
+	}
+	runner, err := s.newRunner(s.config, e.Competitor)
 	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+		return err
 	}
+	s.runners[e.Competitor] = runner
 
-	fmt.Printf("[%s] The competitor(%d) has started\n", time, runnerID)
-	if !started {
-		fmt.Printf("[%s] The competitor(%d) is disqualified\n", time, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.Registered})
+	return nil
+}
+
+func (s *EventLogger) handleSetRunnerTime(e event.Event) error {
+	if err := s.runners[e.Competitor].SetStartTime(e.StartTime); err != nil {
+		return err
 	}
+
+	s.emit(e, outgoing.Event{Kind: outgoing.StartTimeSet, StartTime: e.StartTime})
+	return nil
 }
 
-func (s *EventLogger) handleRunnerStartFire(time string, runnerID int, firingRangeStr string) {
-	s.checkRunnerExist(runnerID)
+func (s *EventLogger) handleRunnerOnStart(e event.Event) error {
+	if err := s.runners[e.Competitor].OnLine(); err != nil {
+		return err
+	}
+
+	s.emit(e, outgoing.Event{Kind: outgoing.OnStartLine})
+	return nil
+}
 
-	firingRange, err := strconv.Atoi(firingRangeStr)
+func (s *EventLogger) handleStartRunner(e event.Event) error {
+	started, err := s.runners[e.Competitor].Start(e.Time)
 	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+		return err
 	}
 
-	if status := s.firingRanges[firingRange]; status == rangeOccupied {
-		s.logger.Fatalf("Err at event %s: %s", time, "range occupied")
+	s.emit(e, outgoing.Event{Kind: outgoing.Started})
+	if !started {
+		s.emit(e, outgoing.Event{Kind: outgoing.Disqualified, Reason: model.LateStartReason})
+		return nil
 	}
-	s.firingRanges[firingRange] = rangeOccupied
+	s.notifyStarted(e)
+	return nil
+}
 
-	err = s.runners[runnerID].StartFiring(firingRange)
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+func (s *EventLogger) handleRunnerStartFire(e event.Event) error {
+	if status := s.firingRanges[e.FiringRange]; status == rangeOccupied {
+		return errors.New("range occupied")
+	}
+	if err := s.runners[e.Competitor].StartFiring(e.Time, e.FiringRange); err != nil {
+		return err
 	}
+	s.firingRanges[e.FiringRange] = rangeOccupied
 
-	fmt.Printf("[%s] The competitor(%d) is on the firing range(%d)\n", time, runnerID, firingRange)
+	s.emit(e, outgoing.Event{Kind: outgoing.OnFiringRange, FiringRange: e.FiringRange})
+	return nil
 }
 
-func (s *EventLogger) handleRunnerHitTarget(time string, runnerID int, targetStr string) {
-	s.checkRunnerExist(runnerID)
-
-	target, err := strconv.Atoi(targetStr)
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+func (s *EventLogger) handleRunnerHitTarget(e event.Event) error {
+	if err := s.runners[e.Competitor].HitTarget(e.Target); err != nil {
+		return err
 	}
 
-	err = s.runners[runnerID].HitTarget(target)
+	s.emit(e, outgoing.Event{Kind: outgoing.TargetHit, Target: e.Target})
+	return nil
+}
+
+func (s *EventLogger) handleRunnerQuitFire(e event.Event) error {
+	shooting, err := s.runners[e.Competitor].QuitFiring(e.Time)
 	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+		return err
 	}
+	s.firingRanges[shooting.FiringRange] = rangeFree
 
-	fmt.Printf("[%s] The target(%d) has been hit by competitor(%d)\n", time, target, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.LeftFiringRange, FiringRange: shooting.FiringRange})
+	s.notifyShooting(e, shooting)
+	return nil
 }
 
-func (s *EventLogger) handleRunnerQuitFire(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
-
-	firingRange, err := s.runners[runnerID].QuitFiring()
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+func (s *EventLogger) handleRunnerEnterPenalty(e event.Event) error {
+	if err := s.runners[e.Competitor].StartPenalty(e.Time); err != nil {
+		return err
 	}
-	s.firingRanges[firingRange] = rangeFree
 
-	fmt.Printf("[%s] The competitor(%d) left the firing range\n", time, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.EnteredPenalty})
+	return nil
 }
 
-func (s *EventLogger) handleRunnerEnterPenalty(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
-
-	err := s.runners[runnerID].StartPenalty(time)
+func (s *EventLogger) handleRunnerLeftPenalty(e event.Event) error {
+	served, err := s.runners[e.Competitor].QuitPenalty(e.Time)
 	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+		return err
 	}
 
-	fmt.Printf("[%s] The competitor(%d) entered the penalty laps\n", time, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.LeftPenalty})
+	s.notifyPenalty(e, served)
+	return nil
 }
 
-func (s *EventLogger) handleRunnerLeftPenalty(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
-
-	err := s.runners[runnerID].QuitPenalty(time)
+func (s *EventLogger) handleRunnerEndMain(e event.Event) error {
+	finished, err := s.runners[e.Competitor].FinishLap(e.Time)
 	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+		return err
 	}
 
-	fmt.Printf("[%s] The competitor(%d) left the penalty laps\n", time, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.EndedMainLap})
+	if finished {
+		s.emit(e, outgoing.Event{Kind: outgoing.Finished})
+	}
+	s.notifyLap(e, finished)
+	return nil
 }
 
-func (s *EventLogger) handleRunnerEndMain(time string, runnerID int) {
-	s.checkRunnerExist(runnerID)
+func (s *EventLogger) handleRunnerCantRun(e event.Event) error {
+	s.releaseFiringRange(e.Competitor)
+	if err := s.runners[e.Competitor].QuitRunning(e.Reason); err != nil {
+		return err
+	}
+
+	s.emit(e, outgoing.Event{Kind: outgoing.CantContinue, Reason: e.Reason})
+	return nil
+}
 
-	finished, err := s.runners[runnerID].FinishLap(time)
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+func (s *EventLogger) handleAdjustRunnerTime(e event.Event) error {
+	if err := s.runners[e.Competitor].AdjustTime(e.Delta, e.Reason); err != nil {
+		return err
 	}
 
-	fmt.Printf("[%s] The competitor(%d) ended the main lap\n", time, runnerID)
+	s.emit(e, outgoing.Event{Kind: outgoing.TimeAdjusted, Delta: e.Delta, Reason: e.Reason})
+	return nil
+}
 
-	if finished {
-		fmt.Printf("[%s] The competitor(%d) has finished\n", time, runnerID)
+func (s *EventLogger) handleDisqualifyRunner(e event.Event) error {
+	s.releaseFiringRange(e.Competitor)
+	if err := s.runners[e.Competitor].Disqualify(e.Reason); err != nil {
+		return err
 	}
+
+	s.emit(e, outgoing.Event{Kind: outgoing.JuryDisqualified, Reason: e.Reason})
+	return nil
 }
 
-func (s *EventLogger) handleRunnerCantRun(time string, runnerID int, comment string) {
-	s.checkRunnerExist(runnerID)
+func (s *EventLogger) handleLapRunner(e event.Event) error {
+	s.releaseFiringRange(e.Competitor)
+	if err := s.runners[e.Competitor].Lapped(); err != nil {
+		return err
+	}
 
-	err := s.runners[runnerID].QuitRunning()
-	if err != nil {
-		s.logger.Fatalf("Err at event %s: %v", time, err)
+	s.emit(e, outgoing.Event{Kind: outgoing.Lapped})
+	return nil
+}
+
+// releaseFiringRange frees the range of a runner leaving the race while firing
+func (s *EventLogger) releaseFiringRange(runnerID int) {
+	if firingRange, ok := s.runners[runnerID].FiringRange(); ok {
+		s.firingRanges[firingRange] = rangeFree
 	}
+}
 
-	fmt.Printf("[%s] The competitor(%d) can`t continue: %s\n", time, runnerID, comment)
+// emit queues outgoing event caused by the incoming one, it's delivered
+// to the sink once the incoming event is accepted
+func (s *EventLogger) emit(in event.Event, out outgoing.Event) {
+	if s.silent {
+		return
+	}
+	out.Seq = in.Seq
+	out.Time = in.Time
+	out.Competitor = in.Competitor
+	if competitor, ok := s.startList[in.Competitor]; ok {
+		out.Details = &competitor
+	}
+	s.outbox = append(s.outbox, out)
 }
 
-func (s *EventLogger) checkRunnerExist(runnerID int) {
-	if _, ok := s.runners[runnerID]; !ok {
-		s.logger.Fatalf("No such runner registered: %d", runnerID)
+// deliver sends outgoing events of the accepted event to the sink
+func (s *EventLogger) deliver() error {
+	var errs []error
+	for _, out := range s.outbox {
+		if err := s.sink.Emit(out); err != nil {
+			errs = append(errs, fmt.Errorf("emitting %s event: %w", out.Kind, err))
+		}
+	}
+	s.outbox = s.outbox[:0]
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrUndelivered, err)
 	}
+	return nil
 }
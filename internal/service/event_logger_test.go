@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"reflect"
+	"testing"
+)
+
+func TestDisqualifiedIgnoresCourseEvents(t *testing.T) {
+	s := newTestEngine(t)
+	events := competitorEvents(t, 1, 1)
+	for _, e := range events[:5] {
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := submitLine(t, s, "[10:10:35.000] 16 1 SHOOTING"); err != nil {
+		t.Fatal(err)
+	}
+	// the rest of the race is still on the feed
+	for _, e := range events[5:] {
+		if _, err := s.Submit(e); err != nil {
+			t.Errorf("Expected %s of the disqualified competitor to be ignored, got %v", e, err)
+		}
+	}
+	if err := submitLine(t, s, "[10:40:00.000] 11 1 FELL"); err != nil {
+		t.Errorf("Expected quitting of the disqualified competitor to be ignored, got %v", err)
+	}
+
+	result, _ := s.Result(1)
+	if result.Status != model.StatusDSQ || result.Reason != "SHOOTING" || len(result.Laps) != 1 || result.Hits != 0 {
+		t.Errorf("Expected DSQ SHOOTING after the first lap, got %+v", result)
+	}
+	if err := submitLine(t, s, "[10:45:00.000] 16 1 EQUIPMENT"); err == nil {
+		t.Error("Expected the second disqualification to be rejected")
+	}
+}
+
+func TestSinkFailure(t *testing.T) {
+	s := newTestEngine(t)
+	var delivered []outgoing.Kind
+	s.SetSink(outgoing.SinkFunc(func(e outgoing.Event) error {
+		if e.Kind == outgoing.Registered {
+			return errors.New("sink is down")
+		}
+		delivered = append(delivered, e.Kind)
+		return nil
+	}))
+	events := competitorEvents(t, 1, 1)
+
+	// the registration is accepted though its outgoing event is lost
+	seq, err := s.Submit(events[0])
+	if !errors.Is(err, ErrUndelivered) || seq != 1 {
+		t.Fatalf("Expected event 1 accepted with %v, got %d and %v", ErrUndelivered, seq, err)
+	}
+	if _, ok := s.Result(1); !ok {
+		t.Fatal("Expected competitor(1) registered")
+	}
+	if _, err := s.Submit(events[0]); err == nil || errors.Is(err, ErrUndelivered) {
+		t.Errorf("Expected the second registration to be rejected, got %v", err)
+	}
+	// outgoing events of a rejected event are never delivered
+	if err := submitLine(t, s, "[09:20:00.000] 3 1"); err == nil {
+		t.Error("Expected the start line before the draw to be rejected")
+	}
+	if seq, err := s.Submit(events[1]); err != nil || seq != 2 {
+		t.Fatalf("Expected event 2 accepted, got %d and %v", seq, err)
+	}
+	if want := []outgoing.Kind{outgoing.StartTimeSet}; !reflect.DeepEqual(delivered, want) {
+		t.Errorf("Expected delivered %v, got %v", want, delivered)
+	}
+}
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk is full")
+}
+
+func TestResultingTableWriteFailure(t *testing.T) {
+	for _, format := range []Format{FormatText, FormatJSON} {
+		s := newTestEngine(t)
+		if err := s.SetFormat(format); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Submit(competitorEvents(t, 1, 1)[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.WriteResultingTable(failingWriter{}); err == nil {
+			t.Errorf("Expected %s table write to fail", format)
+		}
+	}
+}
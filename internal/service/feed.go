@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"racingMetrics/internal/event"
+	"slices"
+	"sync"
+)
+
+const (
+	errFeedOrder  Err = "event is earlier than the previous one of the feed"
+	errFeedClosed Err = "feed is closed"
+)
+
+// Feed is a live source of events ordered by time, e.g. the start, a firing range or the finish.
+// Events of all open feeds are merged by time: an event is applied once every other open feed
+// has submitted a later event or advanced past its time. So events of a competitor sent by
+// different feeds are applied in the order of their times, events of equal time from different
+// feeds in the order of feed creation. A feed that stays silent holds back the others,
+// it's to be advanced by heartbeats or closed
+type Feed struct {
+	s       *EventLogger
+	pending []event.Event
+	last    int
+	closed  bool
+	errs    []error
+}
+
+// NewFeed opens a feed of the engine. Events sent by Submit of the engine
+// bypass feeds and are applied right away
+func (s *EventLogger) NewFeed() *Feed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.released == nil {
+		s.released = sync.NewCond(&s.mu)
+	}
+	f := &Feed{s: s}
+	s.feeds = append(s.feeds, f)
+	return f
+}
+
+// Submit queues the event of the feed and applies events of all feeds that are due.
+// It returns errors of events of the feed rejected since the previous call.
+// A rejected event isn't numbered and doesn't change the state
+func (f *Feed) Submit(e event.Event) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	if f.closed {
+		return errFeedClosed
+	}
+	if err := f.s.restoreOnce(); err != nil {
+		return err
+	}
+	if e.Time < f.last {
+		return fmt.Errorf("%w: %s", errFeedOrder, e)
+	}
+	f.last = e.Time
+	f.pending = append(f.pending, e)
+	f.s.release()
+	return f.report()
+}
+
+// Advance promises that the feed has no events earlier than the time,
+// so events of other feeds up to the time can be applied
+func (f *Feed) Advance(time int) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	if f.closed {
+		return errFeedClosed
+	}
+	if err := f.s.restoreOnce(); err != nil {
+		return err
+	}
+	f.last = max(f.last, time)
+	f.s.release()
+	return f.report()
+}
+
+// Close ends the feed and waits until its queued events are applied, which other open
+// feeds may hold back. It returns errors of events of the feed rejected since the previous call
+func (f *Feed) Close() error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	if f.closed {
+		return errFeedClosed
+	}
+	if err := f.s.restoreOnce(); err != nil {
+		return err
+	}
+	f.closed = true
+	f.s.release()
+	for len(f.pending) > 0 {
+		f.s.released.Wait()
+	}
+	return f.report()
+}
+
+// report returns and forgets errors of rejected events of the feed
+func (f *Feed) report() error {
+	err := errors.Join(f.errs...)
+	f.errs = nil
+	return err
+}
+
+// release applies queued events of feeds in the order of time while no open feed
+// can send one to be applied before, closed feeds without queued events are dropped
+func (s *EventLogger) release() {
+	for s.releaseNext() {
+	}
+	s.feeds = slices.DeleteFunc(s.feeds, func(f *Feed) bool {
+		return f.closed && len(f.pending) == 0
+	})
+	s.released.Broadcast()
+}
+
+// releaseNext applies the earliest queued event if it's due
+func (s *EventLogger) releaseNext() bool {
+	next := -1
+	for i, f := range s.feeds {
+		if len(f.pending) > 0 && (next < 0 || f.pending[0].Time < s.feeds[next].pending[0].Time) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return false
+	}
+	e := s.feeds[next].pending[0]
+	for i, f := range s.feeds {
+		if len(f.pending) == 0 && !f.closed && (f.last < e.Time || f.last == e.Time && i < next) {
+			return false
+		}
+	}
+	feed := s.feeds[next]
+	feed.pending = feed.pending[1:]
+
+	e.Seq = s.seq + 1
+	if err := s.process(e); err != nil {
+		feed.errs = append(feed.errs, fmt.Errorf("event %s: %w", e, err))
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/startlist"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files of testdata cases")
+
+// goldenFormats maps resulting table formats to their golden files
+var goldenFormats = map[Format]string{
+	FormatText: "table.txt",
+	FormatJSON: "table.json",
+}
+
+// TestGolden runs every testdata case: config.json and events with an optional
+// startlist.csv, comparing the event log with output.log and output.jsonl and
+// the resulting table with a golden file per format. Run with -update to rewrite goldens.
+func TestGolden(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join("testdata", "*", "events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("No testdata cases")
+	}
+
+	for _, eventsPath := range cases {
+		dir := filepath.Dir(eventsPath)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			for format, golden := range goldenFormats {
+				eventLog, jsonLog, table := runCase(t, dir, format)
+				if format == FormatText {
+					compareGolden(t, filepath.Join(dir, "output.log"), eventLog)
+					compareGolden(t, filepath.Join(dir, "output.jsonl"), jsonLog)
+				}
+				compareGolden(t, filepath.Join(dir, golden), table)
+			}
+		})
+	}
+}
+
+func runCase(t *testing.T, dir string, format Format) ([]byte, []byte, []byte) {
+	t.Helper()
+	s := NewRunLog(filepath.Join(dir, "config.json"), filepath.Join(dir, "events"), log.New(io.Discard, "", 0))
+	eventLog, jsonLog, table := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	s.SetOutput(eventLog, table)
+	s.SetSink(outgoing.MultiSink{outgoing.NewTextSink(eventLog), outgoing.NewJSONSink(jsonLog)})
+	if err := s.SetFormat(format); err != nil {
+		t.Fatal(err)
+	}
+
+	startListPath := filepath.Join(dir, "startlist.csv")
+	if _, err := os.Stat(startListPath); err == nil {
+		competitors, err := startlist.Load(startListPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetStartList(competitors)
+	}
+
+	if err := s.RunEvents(context.Background()); err != nil {
+		t.Fatalf("Running %s: %v", dir, err)
+	}
+	if err := s.PrintResultingTable(); err != nil {
+		t.Fatal(err)
+	}
+	return eventLog.Bytes(), jsonLog.Bytes(), table.Bytes()
+}
+
+func compareGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Missing golden file %s, run go test -update", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	gotLines, wantLines := bytes.Split(got, []byte("\n")), bytes.Split(want, []byte("\n"))
+	for i := 0; i < max(len(gotLines), len(wantLines)); i++ {
+		var gotLine, wantLine []byte
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if !bytes.Equal(gotLine, wantLine) {
+			t.Errorf("%s:%d mismatch, run go test -update to accept\ngot:  %s\nwant: %s", path, i+1, gotLine, wantLine)
+			return
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+)
+
+// Moment is the event a hook is called for
+type Moment struct {
+	Seq        int
+	Time       int
+	Competitor int
+}
+
+// ShootingInfo is a completed firing range stage
+type ShootingInfo struct {
+	Moment
+	model.Shooting
+}
+
+// PenaltyInfo is a served penalty lap, Served is time spent on it
+type PenaltyInfo struct {
+	Moment
+	Served int
+}
+
+// LapInfo is a completed main lap, Lap counts from 1
+type LapInfo struct {
+	Moment
+	Lap     int
+	LapTime int
+}
+
+// FinishInfo is a finished competitor
+type FinishInfo struct {
+	Moment
+	Result model.Result
+}
+
+// StatusInfo is a status change of a competitor, including the ones caused by jury corrections
+type StatusInfo struct {
+	Moment
+	From model.Status
+	To   model.Status
+}
+
+// Hooks are callbacks on race moments, nil ones are skipped.
+// They are called synchronously after the outgoing event is emitted,
+// and aren't called for events restored from a journal or recomputed after corrections.
+// Hooks run holding the engine lock, so they must not call EventLogger
+type Hooks struct {
+	Started       func(Moment)
+	ShootingDone  func(ShootingInfo)
+	PenaltyServed func(PenaltyInfo)
+	LapCompleted  func(LapInfo)
+	Finished      func(FinishInfo)
+	StatusChanged func(StatusInfo)
+}
+
+// AddHooks registers callbacks on race moments
+func (s *EventLogger) AddHooks(h Hooks) {
+	s.hooks = append(s.hooks, h)
+}
+
+// silence stops emitting outgoing events and calling hooks until restore is called
+func (s *EventLogger) silence() (restore func()) {
+	silent, hooks := s.silent, s.hooks
+	s.silent, s.hooks = true, nil
+	return func() {
+		s.silent, s.hooks = silent, hooks
+	}
+}
+
+func moment(e event.Event) Moment {
+	return Moment{Seq: e.Seq, Time: e.Time, Competitor: e.Competitor}
+}
+
+// status returns status of the competitor, empty if not registered
+func (s *EventLogger) status(runnerID int) model.Status {
+	if runner, ok := s.runners[runnerID]; ok {
+		return runner.Status()
+	}
+	return ""
+}
+
+// notifyStatus calls StatusChanged hooks if status of a registered competitor changed
+func (s *EventLogger) notifyStatus(e event.Event, from model.Status) {
+	to := s.status(e.Competitor)
+	if from == "" || from == to {
+		return
+	}
+	for _, h := range s.hooks {
+		if h.StatusChanged != nil {
+			h.StatusChanged(StatusInfo{Moment: moment(e), From: from, To: to})
+		}
+	}
+}
+
+func (s *EventLogger) notifyStarted(e event.Event) {
+	for _, h := range s.hooks {
+		if h.Started != nil {
+			h.Started(moment(e))
+		}
+	}
+}
+
+func (s *EventLogger) notifyShooting(e event.Event, shooting model.Shooting) {
+	for _, h := range s.hooks {
+		if h.ShootingDone != nil {
+			h.ShootingDone(ShootingInfo{Moment: moment(e), Shooting: shooting})
+		}
+	}
+}
+
+func (s *EventLogger) notifyPenalty(e event.Event, served int) {
+	for _, h := range s.hooks {
+		if h.PenaltyServed != nil {
+			h.PenaltyServed(PenaltyInfo{Moment: moment(e), Served: served})
+		}
+	}
+}
+
+func (s *EventLogger) notifyLap(e event.Event, finished bool) {
+	if len(s.hooks) == 0 {
+		return
+	}
+	result := s.runners[e.Competitor].GetResult()
+	result.Competitor = s.startList[e.Competitor]
+	// a runner of a custom format may keep no lap results
+	if len(result.Laps) > 0 {
+		lap := LapInfo{Moment: moment(e), Lap: len(result.Laps), LapTime: result.Laps[len(result.Laps)-1].Time}
+		for _, h := range s.hooks {
+			if h.LapCompleted != nil {
+				h.LapCompleted(lap)
+			}
+		}
+	}
+	if !finished {
+		return
+	}
+	for _, h := range s.hooks {
+		if h.Finished != nil {
+			h.Finished(FinishInfo{Moment: moment(e), Result: result})
+		}
+	}
+}
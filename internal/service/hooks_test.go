@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/outgoing"
+	"slices"
+	"testing"
+)
+
+const hooksEvents = `[09:30:00.000] 1 1
+[09:31:00.000] 2 1 10:00:00.000
+[09:59:00.000] 3 1
+[10:00:01.000] 4 1
+[10:05:00.000] 5 1 1
+[10:05:05.000] 6 1 1
+[10:05:06.000] 6 1 2
+[10:05:30.000] 7 1
+[10:05:40.000] 8 1
+[10:06:40.000] 9 1
+[10:10:00.000] 10 1
+[10:20:00.000] 10 1
+[10:21:00.000] 14 1 12
+`
+
+func TestHooks(t *testing.T) {
+	dir := t.TempDir()
+	eventsPath := filepath.Join(dir, "events")
+	if err := os.WriteFile(eventsPath, []byte(hooksEvents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewRunLog(filepath.Join("testdata", "sunny_5_skiers", "config.json"), eventsPath, log.New(io.Discard, "", 0))
+	s.SetSink(outgoing.Discard)
+
+	var calls []string
+	s.AddHooks(Hooks{
+		Started: func(m Moment) {
+			calls = append(calls, fmt.Sprintf("%d started", m.Seq))
+		},
+		ShootingDone: func(i ShootingInfo) {
+			calls = append(calls, fmt.Sprintf("%d shooting %d: %d/%d in %d", i.Seq, i.FiringRange, i.Hits, i.Shots, i.End-i.Start))
+		},
+		PenaltyServed: func(i PenaltyInfo) {
+			calls = append(calls, fmt.Sprintf("%d penalty %d", i.Seq, i.Served))
+		},
+		LapCompleted: func(i LapInfo) {
+			calls = append(calls, fmt.Sprintf("%d lap %d: %d", i.Seq, i.Lap, i.LapTime))
+		},
+		Finished: func(i FinishInfo) {
+			calls = append(calls, fmt.Sprintf("%d finished %d", i.Seq, i.Result.TotalTime))
+		},
+		StatusChanged: func(i StatusInfo) {
+			calls = append(calls, fmt.Sprintf("%d status %s -> %s", i.Seq, i.From, i.To))
+		},
+	})
+	s.AddHooks(Hooks{})
+	if err := s.RunEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"4 started",
+		"4 status DNS -> Running",
+		"8 shooting 1: 2/5 in 30000",
+		"10 penalty 60000",
+		"11 lap 1: 599000",
+		"12 lap 2: 600000",
+		"12 finished 1200000",
+		"12 status Running -> Finished",
+		"13 status Finished -> Running",
+	}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("Expected hook calls\n%q\ngot\n%q", expected, calls)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/journal"
+	"racingMetrics/internal/model"
+	"strings"
+	"testing"
+)
+
+// runJournaled runs events of the case recording them to the journal, returns the event log
+func runJournaled(t *testing.T, dir, eventsPath, journalPath string, table io.Writer) []byte {
+	t.Helper()
+	j, err := journal.Open(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := j.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	s := NewRunLog(filepath.Join(dir, "config.json"), eventsPath, log.New(io.Discard, "", 0))
+	eventLog := &bytes.Buffer{}
+	s.SetOutput(eventLog, table)
+	s.SetJournal(j)
+	if err := s.RunEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PrintResultingTable(); err != nil {
+		t.Fatal(err)
+	}
+	return eventLog.Bytes()
+}
+
+func TestJournalReplay(t *testing.T) {
+	dir := filepath.Join("testdata", "sunny_5_skiers")
+	eventsPath := filepath.Join(dir, "events")
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	halfPath := filepath.Join(t.TempDir(), "events")
+	if err := os.WriteFile(halfPath, []byte(strings.Join(lines[:len(lines)/2], "")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	journalPath := filepath.Join(t.TempDir(), "journal")
+
+	// the first run is interrupted halfway, the restarted one gets the full file
+	firstLog := runJournaled(t, dir, halfPath, journalPath, io.Discard)
+	table := &bytes.Buffer{}
+	secondLog := runJournaled(t, dir, eventsPath, journalPath, table)
+
+	compareGolden(t, filepath.Join(dir, "table.txt"), table.Bytes())
+	expected, err := os.ReadFile(filepath.Join(dir, "output.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// journaled events are replayed silently and skipped in the events file
+	if got := string(firstLog) + string(secondLog); got != string(expected) {
+		t.Errorf("Expected event logs of both runs to make output.log, got:\n%s", got)
+	}
+}
+
+func TestJournalSubmitRestart(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "journal")
+	events := competitorEvents(t, 1, 1)
+
+	// the first engine takes live events until it's stopped after the second lap
+	j, err := journal.Open(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestEngine(t)
+	s.SetJournal(j)
+	for _, e := range events[:len(events)-1] {
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the restarted engine continues numbering after the journal
+	if j, err = journal.Open(journalPath); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	s = newTestEngine(t)
+	s.SetJournal(j)
+	seq, err := s.Submit(events[len(events)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq != len(events) || j.Seq() != len(events) {
+		t.Errorf("Expected event %d in the engine and the journal, got %d and %d", len(events), seq, j.Seq())
+	}
+	// corrections refer to events accepted before the restart
+	if err := submitLine(t, s, "[11:00:00.000] 14 1 7"); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := s.Result(1); result.Status != model.StatusFinished || result.Hits != 0 {
+		t.Errorf("Expected finished competitor(1) with the hit voided, got %s with %d hits", result.Status, result.Hits)
+	}
+}
+
+func TestJournalWriteFailure(t *testing.T) {
+	events := competitorEvents(t, 1, 1)
+	j, err := journal.Open(filepath.Join(t.TempDir(), "journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestEngine(t)
+	s.SetJournal(j)
+	if _, err := s.Submit(events[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the event is accepted, but the journal lost it, so the engine stops
+	seq, err := s.Submit(events[1])
+	if !errors.Is(err, ErrUnrecorded) || seq != 2 {
+		t.Fatalf("Expected event 2 accepted with ErrUnrecorded, got %d, %v", seq, err)
+	}
+	if seq, err := s.Submit(events[2]); !errors.Is(err, ErrUnrecorded) || seq != 0 {
+		t.Errorf("Expected the stopped engine to reject events, got %d, %v", seq, err)
+	}
+}
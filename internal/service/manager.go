@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/outgoing"
+	"sort"
+	"sync"
+)
+
+const (
+	errDuplicateRace Err = "race is already hosted"
+	errUnknownRace   Err = "no such race"
+)
+
+// Manager hosts independent races keyed by race ID, its methods are safe for concurrent use
+type Manager struct {
+	mu     sync.RWMutex
+	races  map[string]*EventLogger
+	sink   outgoing.Sink
+	format Format
+}
+
+// racesDocument is a JSON document of resulting tables of all races
+type racesDocument struct {
+	Races []raceDocument `json:"races"`
+}
+
+type raceDocument struct {
+	Race string `json:"race"`
+	resultsDocument
+}
+
+// NewManager returns Manager emitting outgoing events of all races,
+// tagged with race ID, to the combined sink
+func NewManager(sink outgoing.Sink) *Manager {
+	return &Manager{
+		races:  make(map[string]*EventLogger),
+		sink:   outgoing.NewSyncSink(sink),
+		format: FormatText,
+	}
+}
+
+// SetFormat sets output format of the combined resulting tables
+func (m *Manager) SetFormat(format Format) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.format = format
+	return nil
+}
+
+// Add hosts the race. Its outgoing events go both to its own sink and to the combined one,
+// so the race sink is to be set before
+func (m *Manager) Add(id string, race *EventLogger) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.races[id]; ok {
+		return fmt.Errorf("%w: %s", errDuplicateRace, id)
+	}
+	race.sink = outgoing.MultiSink{race.sink, outgoing.RaceSink(id, m.sink)}
+	m.races[id] = race
+	return nil
+}
+
+// IDs returns sorted IDs of hosted races
+func (m *Manager) IDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.races))
+	for id := range m.races {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Race returns the hosted race
+func (m *Manager) Race(id string) (*EventLogger, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	race, ok := m.races[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownRace, id)
+	}
+	return race, nil
+}
+
+// Apply routes the event to the race and returns error of its engine,
+// a rejected event doesn't affect other races
+func (m *Manager) Apply(id string, e event.Event) error {
+	race, err := m.Race(id)
+	if err != nil {
+		return err
+	}
+	if err := race.Apply(e); err != nil {
+		return fmt.Errorf("race %s: %w", id, err)
+	}
+	return nil
+}
+
+// RunEvents runs events files of all races concurrently. A race stopped by an error
+// doesn't stop others, errors of all races are returned
+func (m *Manager) RunEvents(ctx context.Context) error {
+	ids := m.IDs()
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		race, err := m.Race(id)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := race.RunEvents(ctx); err != nil {
+				errs[i] = fmt.Errorf("race %s: %w", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// PrintResultingTable writes resulting table of the race in its format
+func (m *Manager) PrintResultingTable(id string, w io.Writer) error {
+	race, err := m.Race(id)
+	if err != nil {
+		return err
+	}
+	return race.writeResultingTable(w, race.format)
+}
+
+// PrintResultingTables writes resulting tables of all races ordered by race ID
+func (m *Manager) PrintResultingTables(w io.Writer) error {
+	m.mu.RLock()
+	format := m.format
+	m.mu.RUnlock()
+
+	doc := racesDocument{Races: []raceDocument{}}
+	for _, id := range m.IDs() {
+		race, err := m.Race(id)
+		if err != nil {
+			return err
+		}
+		if format == FormatJSON {
+			doc.Races = append(doc.Races, raceDocument{Race: id, resultsDocument: race.results()})
+		} else {
+			if _, err := fmt.Fprintf(w, "Race %s\n", id); err != nil {
+				return err
+			}
+			if err := race.writeResultingTable(w, FormatText); err != nil {
+				return fmt.Errorf("race %s: %w", id, err)
+			}
+		}
+	}
+	if format != FormatJSON {
+		return nil
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/outgoing"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManager(t *testing.T) {
+	cases := map[string]string{
+		"women": "sunny_5_skiers",
+		"men":   "jury",
+	}
+	var combined bytes.Buffer
+	m := NewManager(outgoing.NewTextSink(&combined))
+	for id, name := range cases {
+		dir := filepath.Join("testdata", name)
+		race := NewRunLog(filepath.Join(dir, "config.json"), filepath.Join(dir, "events"), log.New(io.Discard, "", 0))
+		race.SetSink(outgoing.Discard)
+		if err := m.Add(id, race); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Add("men", &EventLogger{}); !errors.Is(err, errDuplicateRace) {
+		t.Errorf("Expected %v, got %v", errDuplicateRace, err)
+	}
+	if err := m.Apply("juniors", event.Event{}); !errors.Is(err, errUnknownRace) {
+		t.Errorf("Expected %v, got %v", errUnknownRace, err)
+	}
+
+	if err := m.RunEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, name := range cases {
+		var table bytes.Buffer
+		if err := m.PrintResultingTable(id, &table); err != nil {
+			t.Fatal(err)
+		}
+		compareGolden(t, filepath.Join("testdata", name, "table.txt"), table.Bytes())
+
+		// events of a race keep their order in the combined log
+		var raceLog strings.Builder
+		tag := " (" + id + ")"
+		for _, line := range strings.SplitAfter(combined.String(), "\n") {
+			if strings.Contains(line, tag) {
+				raceLog.WriteString(strings.Replace(line, tag, "", 1))
+			}
+		}
+		expected, err := os.ReadFile(filepath.Join("testdata", name, "output.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if raceLog.String() != string(expected) {
+			t.Errorf("Combined log of race %s differs from %s/output.log", id, name)
+		}
+	}
+
+	var tables bytes.Buffer
+	if err := m.PrintResultingTables(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(tables.String(), "Race men\n") || !strings.Contains(tables.String(), "Race women\n") {
+		t.Errorf("Expected tables of all races ordered by ID, got:\n%s", tables.String())
+	}
+}
+
+func TestManagerRejectedEvent(t *testing.T) {
+	events, err := os.ReadFile(filepath.Join("testdata", "sunny_5_skiers", "events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(events), "\n")
+	// juniors stop halfway at an event of an unregistered competitor
+	brokenPath := filepath.Join(t.TempDir(), "events")
+	broken := strings.Join(lines[:50], "") + "[10:20:00.000] 4 42\n" + strings.Join(lines[50:], "")
+	if err := os.WriteFile(brokenPath, []byte(broken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(outgoing.Discard)
+	races := map[string]string{
+		"women":   filepath.Join("testdata", "sunny_5_skiers", "events"),
+		"men":     filepath.Join("testdata", "jury", "events"),
+		"juniors": brokenPath,
+	}
+	for id, eventsPath := range races {
+		configPath := filepath.Join(filepath.Dir(eventsPath), "config.json")
+		if id == "juniors" {
+			configPath = filepath.Join("testdata", "sunny_5_skiers", "config.json")
+		}
+		race := NewRunLog(configPath, eventsPath, log.New(io.Discard, "", 0))
+		race.SetSink(outgoing.Discard)
+		if err := m.Add(id, race); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = m.RunEvents(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "race juniors: event 51") {
+		t.Fatalf("Expected juniors to fail at event 51, got %v", err)
+	}
+	for id, name := range map[string]string{"women": "sunny_5_skiers", "men": "jury"} {
+		var table bytes.Buffer
+		if err := m.PrintResultingTable(id, &table); err != nil {
+			t.Fatal(err)
+		}
+		compareGolden(t, filepath.Join("testdata", name, "table.txt"), table.Bytes())
+	}
+
+	juniors, err := m.Race("juniors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := juniors.Results()
+	e, err := event.ParseEvent("[10:20:00.000] 4 42", 51)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Apply("juniors", e); err == nil {
+		t.Error("Expected the event to be rejected")
+	}
+	if after := juniors.Results(); !reflect.DeepEqual(before, after) {
+		t.Error("Expected the rejected event not to change the race")
+	}
+	// the race goes on after the rejected event
+	e, err = event.ParseEvent(strings.TrimSpace(lines[50]), 51)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Apply("juniors", e); err != nil {
+		t.Errorf("Expected the next event to be applied, got %v", err)
+	}
+}
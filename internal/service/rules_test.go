@@ -0,0 +1,100 @@
+package service
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/rules"
+	"testing"
+)
+
+// drillFormat is a training format registered once per test binary
+const drillFormat = "service_test_drill"
+
+// drillRunner is a training competitor: the course is the individual one,
+// but only the total time is kept
+type drillRunner struct {
+	rules.Runner
+}
+
+// GetResult returns result without laps
+func (r drillRunner) GetResult() model.Result {
+	result := r.Runner.GetResult()
+	result.Laps = nil
+	return result
+}
+
+func init() {
+	individual, err := rules.Lookup(rules.Individual)
+	if err != nil {
+		panic(err)
+	}
+	rules.Register(drillFormat, func(config model.Config, runnerID int) (rules.Runner, error) {
+		runner, err := individual(config, runnerID)
+		if err != nil {
+			return nil, err
+		}
+		return drillRunner{Runner: runner}, nil
+	})
+}
+
+func TestLoadConfigFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: ""},
+		{format: rules.Individual},
+		{format: "relay", wantErr: true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		data := `{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "firingLines": 2, "start": "10:00:00.000", "startDelta": "00:01:30", "format": "` + tt.format + `"}`
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		config, err := LoadConfig(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LoadConfig with format %q: unexpected error %v", tt.format, err)
+		}
+		if err == nil && config.Format != tt.format {
+			t.Errorf("Expected format %q, got %q", tt.format, config.Format)
+		}
+	}
+}
+
+func TestCustomFormat(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("testdata", "sunny_5_skiers", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Format = drillFormat
+	s, err := NewEventLogger(config, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetSink(outgoing.Discard)
+	var laps []LapInfo
+	finished := false
+	s.AddHooks(Hooks{
+		LapCompleted: func(lap LapInfo) { laps = append(laps, lap) },
+		Finished:     func(FinishInfo) { finished = true },
+	})
+
+	for _, e := range competitorEvents(t, 1, 1) {
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, _ := s.Result(1)
+	if result.Status != model.StatusFinished || len(result.Laps) != 0 {
+		t.Errorf("Expected the drill competitor finished without laps, got %+v", result)
+	}
+	// a runner keeping no lap results completes no laps for hooks, but finishes
+	if len(laps) != 0 || !finished {
+		t.Errorf("Expected only the finish hook, got laps %+v, finished %t", laps, finished)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/outgoing"
+	"racingMetrics/internal/simulate"
+	"testing"
+)
+
+// simulatedRace writes config and events of a generated race and returns their paths
+func simulatedRace(tb testing.TB, competitors int) (string, string) {
+	tb.Helper()
+	config := model.Config{
+		Laps:          2,
+		LapLen:        3500,
+		PenaltyLen:    150,
+		FiringLines:   30,
+		Start:         "10:00:00.000",
+		StartDelta:    "00:00:03",
+		TargetsAmount: 5,
+	}
+	opts := simulate.DefaultOptions()
+	opts.Competitors = competitors
+	lines, err := simulate.Generate(config, opts)
+	if err != nil {
+		tb.Fatalf("Generate failed: %v", err)
+	}
+
+	dir := tb.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	eventsPath := filepath.Join(dir, "events")
+	data, err := json.Marshal(config)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, data, 0o600); err != nil {
+		tb.Fatal(err)
+	}
+	file, err := os.Create(eventsPath)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer file.Close()
+	if err := simulate.Write(file, lines); err != nil {
+		tb.Fatal(err)
+	}
+	return configPath, eventsPath
+}
+
+func TestSimulatedRace(t *testing.T) {
+	configPath, eventsPath := simulatedRace(t, 500)
+	s := NewRunLog(configPath, eventsPath, log.New(io.Discard, "", 0))
+	s.SetSink(outgoing.Discard)
+	if err := s.RunEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.runners) != 500 {
+		t.Fatalf("Expected 500 competitors, got %d", len(s.runners))
+	}
+	finished := 0
+	for _, runner := range s.runners {
+		if runner.GetResult().Status == model.StatusFinished {
+			finished++
+		}
+	}
+	if finished == 0 {
+		t.Errorf("Expected finished competitors")
+	}
+}
+
+func BenchmarkSimulatedRace(b *testing.B) {
+	configPath, eventsPath := simulatedRace(b, 5000)
+	for b.Loop() {
+		s := NewRunLog(configPath, eventsPath, log.New(io.Discard, "", 0))
+		s.SetSink(outgoing.Discard)
+		if err := s.RunEvents(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"racingMetrics/internal/model"
+	"racingMetrics/internal/rules"
+)
+
+// snapshotVersion is bumped on every incompatible change of snapshot
+const snapshotVersion = 4
+
+const (
+	errSnapshotVersion Err = "unsupported snapshot version"
+	errSnapshotConfig  Err = "snapshot was taken with another config"
+)
+
+// snapshot is a serialized EventLogger state after event Seq
+type snapshot struct {
+	Version      int                     `json:"version"`
+	Seq          int                     `json:"seq"`
+	Config       model.Config            `json:"config"`
+	Runners      map[int]json.RawMessage `json:"runners"`
+	FiringRanges map[int]rangeStatus     `json:"firingRanges"`
+	History      []historyEntry          `json:"history"`
+	Corrections  []correction            `json:"corrections,omitempty"`
+}
+
+type snapshotter struct {
+	path  string
+	every int
+	seq   int
+}
+
+// SetSnapshots makes EventLogger restore its state from the snapshot at path
+// and rewrite it every n accepted events and after the last one
+func (s *EventLogger) SetSnapshots(path string, every int) {
+	s.snapshots = &snapshotter{
+		path:  path,
+		every: every,
+	}
+}
+
+// restoreSnapshot restores state from the snapshot if it exists
+func (s *EventLogger) restoreSnapshot() error {
+	if s.snapshots == nil {
+		return nil
+	}
+	data, err := os.ReadFile(s.snapshots.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	snap := snapshot{}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("%w: %d", errSnapshotVersion, snap.Version)
+	}
+	if snap.Config != s.config {
+		return errSnapshotConfig
+	}
+
+	runners := make(map[int]rules.Runner, len(snap.Runners))
+	for runnerID, state := range snap.Runners {
+		runner, err := s.newRunner(s.config, runnerID)
+		if err != nil {
+			return fmt.Errorf("competitor(%d): %w", runnerID, err)
+		}
+		if err := runner.UnmarshalJSON(state); err != nil {
+			return fmt.Errorf("competitor(%d): %w", runnerID, err)
+		}
+		runners[runnerID] = runner
+	}
+
+	s.runners = runners
+	s.firingRanges = snap.FiringRanges
+	if s.firingRanges == nil {
+		s.firingRanges = make(map[int]rangeStatus)
+	}
+	s.history = snap.History
+	s.corrections = snap.Corrections
+	s.seq = snap.Seq
+	s.snapshots.seq = snap.Seq
+	return nil
+}
+
+func (s *EventLogger) snapshotIfDue() error {
+	if s.snapshots == nil || s.snapshots.every <= 0 {
+		return nil
+	}
+	if s.seq-s.snapshots.seq >= s.snapshots.every {
+		return s.takeSnapshot()
+	}
+	return nil
+}
+
+func (s *EventLogger) takeSnapshot() error {
+	if s.snapshots == nil || s.snapshots.seq == s.seq {
+		return nil
+	}
+
+	snap := snapshot{
+		Version:      snapshotVersion,
+		Seq:          s.seq,
+		Config:       s.config,
+		Runners:      make(map[int]json.RawMessage, len(s.runners)),
+		FiringRanges: s.firingRanges,
+		History:      s.history,
+		Corrections:  s.corrections,
+	}
+	for runnerID, runner := range s.runners {
+		state, err := runner.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("saving competitor(%d): %w", runnerID, err)
+		}
+		snap.Runners[runnerID] = state
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.snapshots.path, data); err != nil {
+		return err
+	}
+	s.snapshots.seq = s.seq
+	return nil
+}
+
+// writeFileAtomic replaces file at path so that readers see either
+// the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
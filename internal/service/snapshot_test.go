@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/journal"
+	"reflect"
+	"testing"
+)
+
+// submitEvents submits the first n events of the file
+func submitEvents(t *testing.T, s *EventLogger, eventsPath string, n int) {
+	t.Helper()
+	file, err := os.Open(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	decoder, err := event.NewDecoder(file, event.FormatAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range n {
+		e, err := decoder.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	dir := filepath.Join("testdata", "sunny_5_skiers")
+	eventsPath := filepath.Join(dir, "events")
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	journalPath := filepath.Join(t.TempDir(), "journal")
+
+	// snapshots are taken after events 10 and 20, events 21-25 are only journaled
+	j, err := journal.Open(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestEngine(t)
+	s.SetJournal(j)
+	s.SetSnapshots(snapshotPath, 10)
+	submitEvents(t, s, eventsPath, 25)
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := snapshot{}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Seq != 20 {
+		t.Fatalf("Expected snapshot after event 20, got %d", snap.Seq)
+	}
+
+	// restored from the snapshot and the journal tail only
+	restored := restore(t, snapshotPath, journalPath, os.DevNull, io.Discard)
+	if restored.seq != 25 {
+		t.Errorf("Expected state after event 25, got %d", restored.seq)
+	}
+	if !reflect.DeepEqual(restored.Results(), s.Results()) {
+		t.Errorf("Expected restored results %+v, got %+v", s.Results(), restored.Results())
+	}
+
+	// events of the file covered by the snapshot and the journal are skipped
+	table := &bytes.Buffer{}
+	restore(t, snapshotPath, journalPath, eventsPath, table)
+	compareGolden(t, filepath.Join(dir, "table.txt"), table.Bytes())
+}
+
+// restore runs events file of the engine restored from the snapshot and the journal
+func restore(t *testing.T, snapshotPath, journalPath, eventsPath string, table io.Writer) *EventLogger {
+	t.Helper()
+	j, err := journal.Open(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := j.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	s := newTestEngine(t)
+	s.SetOutput(io.Discard, table)
+	s.SetJournal(j)
+	s.SetSnapshots(snapshotPath, 10)
+	s.eventsPath = eventsPath
+	if err := s.RunEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PrintResultingTable(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSnapshotRejected(t *testing.T) {
+	cases := map[string]struct {
+		change func(*snapshot)
+		err    error
+	}{
+		"version": {func(snap *snapshot) { snap.Version++ }, errSnapshotVersion},
+		"config":  {func(snap *snapshot) { snap.Config.Laps++ }, errSnapshotConfig},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+			s := newTestEngine(t)
+			s.SetSnapshots(snapshotPath, 1)
+			submitEvents(t, s, filepath.Join("testdata", "sunny_5_skiers", "events"), 5)
+
+			data, err := os.ReadFile(snapshotPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snap := snapshot{}
+			if err := json.Unmarshal(data, &snap); err != nil {
+				t.Fatal(err)
+			}
+			c.change(&snap)
+			if data, err = json.Marshal(snap); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(snapshotPath, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s = newTestEngine(t)
+			s.SetSnapshots(snapshotPath, 1)
+			if err := s.restoreSnapshot(); !errors.Is(err, c.err) {
+				t.Errorf("Expected %v, got %v", c.err, err)
+			}
+			if s.seq != 0 || len(s.runners) != 0 {
+				t.Errorf("Expected state untouched by rejected snapshot, got event %d and %d competitors", s.seq, len(s.runners))
+			}
+		})
+	}
+}
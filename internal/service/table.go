@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"racingMetrics/internal/classification"
+	"racingMetrics/internal/model"
+	"sort"
+)
+
+// Format is an output format of the resulting table
+type Format string
+
+// Formats
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errUnknownFormat      Err = "unknown output format"
+	errUnknownInputFormat Err = "unknown events file format"
+)
+
+// resultsDocument is a JSON resulting table
+type resultsDocument struct {
+	classification.Document
+	Corrections []correction `json:"corrections,omitempty"`
+}
+
+// SetFormat sets output format of the resulting table
+func (s *EventLogger) SetFormat(format Format) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+	s.format = format
+	return nil
+}
+
+// PrintResultingTable prints overall and per category classifications
+func (s *EventLogger) PrintResultingTable() error {
+	return s.writeResultingTable(s.table, s.format)
+}
+
+// WriteResultingTable writes overall and per category classifications to w
+func (s *EventLogger) WriteResultingTable(w io.Writer) error {
+	return s.writeResultingTable(w, s.format)
+}
+
+// writeResultingTable renders the table before writing it, so a failed write is reported once
+func (s *EventLogger) writeResultingTable(w io.Writer, format Format) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	classifications := s.classify()
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(s.resultsDocument(classifications)); err != nil {
+			return fmt.Errorf("writing resulting table: %w", err)
+		}
+		return nil
+	}
+
+	var table bytes.Buffer
+	for _, c := range classifications {
+		if c.Name == classification.OverallName {
+			fmt.Fprintln(&table, "Resulting table")
+		} else {
+			fmt.Fprintf(&table, "Resulting table %s\n", c.Name)
+		}
+		c.Write(&table)
+	}
+
+	s.printCorrections(&table)
+	if _, err := table.WriteTo(w); err != nil {
+		return fmt.Errorf("writing resulting table: %w", err)
+	}
+	return nil
+}
+
+// Results returns current results of all competitors ordered by runner ID
+func (s *EventLogger) Results() []model.Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	results := s.currentResults()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].RunnerID < results[j].RunnerID
+	})
+	return results
+}
+
+// Result returns current result of the competitor
+func (s *EventLogger) Result(runnerID int) (model.Result, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	runner, ok := s.runners[runnerID]
+	if !ok {
+		return model.Result{}, false
+	}
+	result := runner.GetResult()
+	result.Competitor = s.startList[runnerID]
+	return result, true
+}
+
+// Standings returns current overall and per category classifications
+func (s *EventLogger) Standings() []classification.Classification {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.classify()
+}
+
+// results returns the JSON resulting table document
+func (s *EventLogger) results() resultsDocument {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.resultsDocument(s.classify())
+}
+
+func (s *EventLogger) currentResults() []model.Result {
+	results := make([]model.Result, 0, len(s.runners))
+	for runnerID, runner := range s.runners {
+		result := runner.GetResult()
+		result.Competitor = s.startList[runnerID]
+		results = append(results, result)
+	}
+	return results
+}
+
+// classify returns overall and per category classifications
+func (s *EventLogger) classify() []classification.Classification {
+	return classification.ByCategory(s.currentResults(), s.config.Timing())
+}
+
+func (s *EventLogger) resultsDocument(classifications []classification.Classification) resultsDocument {
+	return resultsDocument{
+		Document: classification.Document{
+			Timing:          s.config.Timing(),
+			Classifications: classifications,
+		},
+		Corrections: s.corrections,
+	}
+}
@@ -0,0 +1,332 @@
+// Package simulate generates synthetic race events
+package simulate
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"sort"
+	"strings"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errNoCompetitors Err = "amount of competitors must be positive"
+	errNoFiringLines Err = "config must have firing lines"
+	errRate          Err = "rates and accuracy must be within [0, 1]"
+	errSpeed         Err = "speeds must be positive"
+	errDayOverflow   Err = "race doesn't fit in a day, reduce competitors or startDelta"
+)
+
+const (
+	// registrationLead is how long before the race start registration opens
+	registrationLead = 90 * 60 * 1000
+	// drawLead is how long before the race start draw is published
+	drawLead = 30 * 60 * 1000
+	// rangeShare is a share of the lap before the firing range
+	rangeShare = 0.6
+	day        = 24 * 3600 * 1000
+)
+
+var quitReasons = []string{"Lost in the forest", "Broken ski", "Feeling unwell", "Broken rifle"}
+
+// Options of the generated race. Speeds are in m/s, times are in milliseconds,
+// spreads are relative standard deviations
+type Options struct {
+	Competitors int
+	Seed        uint64
+
+	LapSpeed       float64
+	LapSpeedSpread float64
+	PenaltySpeed   float64
+
+	// Accuracy is a mean probability to hit a target, AccuracySpread varies it between competitors
+	Accuracy       float64
+	AccuracySpread float64
+	ShotInterval   int
+	RangePrepTime  int
+
+	DNSRate       float64
+	DNFRate       float64
+	LateStartRate float64
+}
+
+// DefaultOptions returns options of an average sprint field
+func DefaultOptions() Options {
+	return Options{
+		Competitors:    50,
+		Seed:           1,
+		LapSpeed:       5,
+		LapSpeedSpread: 0.05,
+		PenaltySpeed:   3,
+		Accuracy:       0.85,
+		AccuracySpread: 0.08,
+		ShotInterval:   4000,
+		RangePrepTime:  15000,
+		DNSRate:        0.02,
+		DNFRate:        0.03,
+		LateStartRate:  0.01,
+	}
+}
+
+// timedLine is a generated event line at the time
+type timedLine struct {
+	time  int
+	order int
+	line  string
+}
+
+// competitor is a simulated runner
+type competitor struct {
+	id        int
+	start     int
+	speed     float64
+	accuracy  float64
+	lap       int
+	lapStart  int
+	quitAtLap int
+}
+
+// action is a scheduled competitor step
+type action struct {
+	time int
+	kind actionKind
+	c    *competitor
+}
+
+type actionKind int
+
+const (
+	arriveRange actionKind = iota
+	finishLap
+)
+
+type generator struct {
+	config    model.Config
+	opts      Options
+	rnd       *rand.Rand
+	events    []timedLine
+	lineFree  []int
+	actions   actionQueue
+	targets   int
+	startDiff int
+	overflow  bool
+}
+
+// Generate returns chronologically ordered incoming event lines
+// of a race of opts.Competitors runners held under the config
+func Generate(config model.Config, opts Options) ([]string, error) {
+	if opts.Competitors <= 0 {
+		return nil, errNoCompetitors
+	}
+	if config.FiringLines <= 0 {
+		return nil, errNoFiringLines
+	}
+	for _, rate := range []float64{opts.Accuracy, opts.DNSRate, opts.DNFRate, opts.LateStartRate} {
+		if rate < 0 || rate > 1 {
+			return nil, errRate
+		}
+	}
+	if opts.LapSpeed <= 0 || opts.PenaltySpeed <= 0 {
+		return nil, errSpeed
+	}
+	start, err := model.ParseTime(config.Start)
+	if err != nil {
+		return nil, fmt.Errorf("config start: %w", err)
+	}
+	interval, err := model.ParseDuration(config.StartDelta)
+	if err != nil {
+		return nil, fmt.Errorf("config startDelta: %w", err)
+	}
+
+	g := &generator{
+		config:    config,
+		opts:      opts,
+		rnd:       rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+		lineFree:  make([]int, config.FiringLines),
+		targets:   max(config.TargetsAmount, 1),
+		startDiff: interval,
+	}
+	g.run(start, interval)
+	if g.overflow {
+		return nil, errDayOverflow
+	}
+
+	sort.SliceStable(g.events, func(i, j int) bool {
+		if g.events[i].time != g.events[j].time {
+			return g.events[i].time < g.events[j].time
+		}
+		return g.events[i].order < g.events[j].order
+	})
+	lines := make([]string, 0, len(g.events))
+	for _, e := range g.events {
+		lines = append(lines, e.line)
+	}
+	return lines, nil
+}
+
+// Write writes event lines
+func Write(w io.Writer, lines []string) error {
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func (g *generator) run(start, interval int) {
+	registration := max(start-registrationLead, 0)
+	draw := max(start-drawLead, registration+g.opts.Competitors)
+
+	for i := 0; i < g.opts.Competitors; i++ {
+		c := &competitor{
+			id:       i + 1,
+			start:    start + i*interval,
+			speed:    g.opts.LapSpeed * math.Max(0.5, 1+g.rnd.NormFloat64()*g.opts.LapSpeedSpread),
+			accuracy: math.Min(1, math.Max(0, g.opts.Accuracy+g.rnd.NormFloat64()*g.opts.AccuracySpread)),
+		}
+		g.emit(event.Event{Time: registration + i, Type: event.Register, Competitor: c.id})
+		g.emit(event.Event{Time: draw, Type: event.SetStartTime, Competitor: c.id, StartTime: c.start})
+
+		if g.rnd.Float64() < g.opts.DNSRate {
+			continue
+		}
+		g.emit(event.Event{Time: c.start - g.between(10000, 60000), Type: event.OnStartLine, Competitor: c.id})
+		if g.rnd.Float64() < g.opts.LateStartRate {
+			g.emit(event.Event{Time: c.start + g.startDiff + g.between(1000, 30000), Type: event.Start, Competitor: c.id})
+			continue
+		}
+		c.lapStart = c.start + g.between(0, 2000)
+		g.emit(event.Event{Time: c.lapStart, Type: event.Start, Competitor: c.id})
+		if g.rnd.Float64() < g.opts.DNFRate {
+			c.quitAtLap = 1 + g.rnd.IntN(max(g.config.Laps, 1))
+		}
+		g.schedule(c)
+	}
+
+	for g.actions.Len() > 0 {
+		a := heap.Pop(&g.actions).(action)
+		switch a.kind {
+		case arriveRange:
+			g.shoot(a)
+		case finishLap:
+			g.finishLap(a)
+		}
+	}
+}
+
+// schedule plans arrival of the competitor on the firing range of the next lap
+func (g *generator) schedule(c *competitor) {
+	c.lap++
+	if c.lap == c.quitAtLap {
+		quitAt := c.lapStart + g.runTime(c, rangeShare*g.rnd.Float64())
+		g.emit(event.Event{Time: quitAt, Type: event.CantContinue, Competitor: c.id, Reason: quitReasons[g.rnd.IntN(len(quitReasons))]})
+		return
+	}
+	heap.Push(&g.actions, action{time: c.lapStart + g.runTime(c, rangeShare), kind: arriveRange, c: c})
+}
+
+// shoot occupies a free firing line or waits for the first one to be freed
+func (g *generator) shoot(a action) {
+	line := -1
+	for i, free := range g.lineFree {
+		if free < a.time && (line < 0 || free < g.lineFree[line]) {
+			line = i
+		}
+	}
+	if line < 0 {
+		earliest := g.lineFree[0]
+		for _, free := range g.lineFree {
+			earliest = min(earliest, free)
+		}
+		heap.Push(&g.actions, action{time: earliest + 1, kind: arriveRange, c: a.c})
+		return
+	}
+
+	c := a.c
+	g.emit(event.Event{Time: a.time, Type: event.OnFiringRange, Competitor: c.id, FiringRange: line + 1})
+	shotTime := a.time + g.between(g.opts.RangePrepTime/2, g.opts.RangePrepTime*3/2)
+	misses := 0
+	for target := 1; target <= g.targets; target++ {
+		if g.rnd.Float64() < c.accuracy {
+			g.emit(event.Event{Time: shotTime, Type: event.HitTarget, Competitor: c.id, Target: target})
+		} else {
+			misses++
+		}
+		shotTime += g.between(g.opts.ShotInterval/2, g.opts.ShotInterval*3/2)
+	}
+	left := shotTime + g.between(1000, 4000)
+	g.emit(event.Event{Time: left, Type: event.LeftFiringRange, Competitor: c.id})
+	g.lineFree[line] = left
+
+	next := left
+	if misses > 0 && g.config.PenaltyLen > 0 {
+		enter := left + g.between(3000, 10000)
+		penalty := float64(misses*g.config.PenaltyLen) / (g.opts.PenaltySpeed * math.Max(0.5, 1+g.rnd.NormFloat64()*g.opts.LapSpeedSpread))
+		next = enter + int(penalty*1000)
+		g.emit(event.Event{Time: enter, Type: event.EnterPenalty, Competitor: c.id})
+		g.emit(event.Event{Time: next, Type: event.LeftPenalty, Competitor: c.id})
+	}
+	heap.Push(&g.actions, action{time: next + g.runTime(c, 1-rangeShare), kind: finishLap, c: c})
+}
+
+func (g *generator) finishLap(a action) {
+	c := a.c
+	g.emit(event.Event{Time: a.time, Type: event.EndMainLap, Competitor: c.id})
+	c.lapStart = a.time
+	if c.lap < g.config.Laps {
+		g.schedule(c)
+	}
+}
+
+// runTime returns time to run the share of the lap with the competitor speed varied per lap
+func (g *generator) runTime(c *competitor, share float64) int {
+	speed := c.speed * math.Max(0.5, 1+g.rnd.NormFloat64()*g.opts.LapSpeedSpread/2)
+	return int(share * float64(g.config.LapLen) / speed * 1000)
+}
+
+// between returns random duration in [from, to)
+func (g *generator) between(from, to int) int {
+	if to <= from {
+		return from
+	}
+	return from + g.rnd.IntN(to-from)
+}
+
+func (g *generator) emit(e event.Event) {
+	if e.Time >= day {
+		g.overflow = true
+	}
+	g.events = append(g.events, timedLine{time: e.Time, order: len(g.events), line: e.String()})
+}
+
+// actionQueue is a min-heap of actions by time
+type actionQueue []action
+
+func (q actionQueue) Len() int { return len(q) }
+func (q actionQueue) Less(i, j int) bool {
+	if q[i].time != q[j].time {
+		return q[i].time < q[j].time
+	}
+	return q[i].c.id < q[j].c.id
+}
+func (q actionQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+// Push adds action
+func (q *actionQueue) Push(x any) { *q = append(*q, x.(action)) }
+
+// Pop removes the last action
+func (q *actionQueue) Pop() any {
+	old := *q
+	a := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return a
+}
@@ -0,0 +1,109 @@
+package simulate
+
+import (
+	"errors"
+	"racingMetrics/internal/event"
+	"racingMetrics/internal/model"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var testConfig = model.Config{
+	Laps:          2,
+	LapLen:        3500,
+	PenaltyLen:    150,
+	FiringLines:   2,
+	Start:         "10:00:00.000",
+	StartDelta:    "00:01:30",
+	TargetsAmount: 5,
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	first, err := Generate(testConfig, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	second, err := Generate(testConfig, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same events for the same seed")
+	}
+}
+
+func TestGenerateIsChronological(t *testing.T) {
+	lines, err := Generate(testConfig, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	prev := -1
+	for _, line := range lines {
+		time, err := model.ParseTime(line[1:strings.Index(line, "]")])
+		if err != nil {
+			t.Fatalf("Bad event %q: %v", line, err)
+		}
+		if time < prev {
+			t.Fatalf("Event %q is out of order", line)
+		}
+		prev = time
+	}
+}
+
+func TestGenerateFiringLinesAreExclusive(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Competitors = 200
+	lines, err := Generate(testConfig, opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	onLine := make(map[string]string)
+	occupied := make(map[string]string)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		switch eventID, _ := strconv.Atoi(fields[1]); event.Type(eventID) {
+		case event.OnFiringRange:
+			if other, ok := occupied[fields[3]]; ok {
+				t.Fatalf("%q: firing line is occupied by competitor(%s)", line, other)
+			}
+			occupied[fields[3]] = fields[2]
+			onLine[fields[2]] = fields[3]
+		case event.LeftFiringRange:
+			delete(occupied, onLine[fields[2]])
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Competitors = 0
+	if _, err := Generate(testConfig, opts); !errors.Is(err, errNoCompetitors) {
+		t.Errorf("Expected %v, got %v", errNoCompetitors, err)
+	}
+
+	opts = DefaultOptions()
+	opts.Accuracy = 1.5
+	if _, err := Generate(testConfig, opts); !errors.Is(err, errRate) {
+		t.Errorf("Expected %v, got %v", errRate, err)
+	}
+
+	for _, change := range []func(*Options){
+		func(opts *Options) { opts.LapSpeed = 0 },
+		func(opts *Options) { opts.PenaltySpeed = -3 },
+	} {
+		opts = DefaultOptions()
+		change(&opts)
+		if _, err := Generate(testConfig, opts); !errors.Is(err, errSpeed) {
+			t.Errorf("Expected %v, got %v", errSpeed, err)
+		}
+	}
+
+	opts = DefaultOptions()
+	opts.Competitors = 3000
+	if _, err := Generate(testConfig, opts); !errors.Is(err, errDayOverflow) {
+		t.Errorf("Expected %v, got %v", errDayOverflow, err)
+	}
+}
@@ -0,0 +1,179 @@
+// Package startlist reads competitor start lists
+package startlist
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/model"
+	"strconv"
+	"strings"
+)
+
+// Err model
+type Err string
+
+// Error returns err text
+func (e Err) Error() string {
+	return string(e)
+}
+
+const (
+	errUnknownFormat Err = "unknown start list format, expected .csv or .json"
+	errNoIDColumn    Err = "start list has no id column"
+	errDuplicateID   Err = "duplicate competitor id in start list"
+	errDuplicateBib  Err = "duplicate bib in start list"
+	errEmpty         Err = "start list has no competitors"
+)
+
+// csvHeader is a header row of written CSV start lists
+var csvHeader = []string{"id", "bib", "name", "nation", "club", "gender", "category", "startTime"}
+
+// Load reads start list from CSV file with a header row
+// (id,bib,name,nation,club,gender,category,startTime in any order, only id is required)
+// or from JSON array of competitors
+func Load(path string) ([]model.Competitor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var competitors []model.Competitor
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		competitors, err = readCSV(file)
+	case ".json":
+		err = json.NewDecoder(file).Decode(&competitors)
+	default:
+		err = errUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("start list %s: %w", path, err)
+	}
+	if len(competitors) == 0 {
+		return nil, fmt.Errorf("start list %s: %w", path, errEmpty)
+	}
+	if err := validate(competitors); err != nil {
+		return nil, fmt.Errorf("start list %s: %w", path, err)
+	}
+	return competitors, nil
+}
+
+func readCSV(r io.Reader) ([]model.Competitor, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, errNoIDColumn
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	competitors := make([]model.Competitor, 0, len(records)-1)
+	for line, record := range records[1:] {
+		id, err := strconv.Atoi(field(record, "id"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid id: %w", line+2, err)
+		}
+		var bib int
+		if bibStr := field(record, "bib"); bibStr != "" {
+			if bib, err = strconv.Atoi(bibStr); err != nil {
+				return nil, fmt.Errorf("line %d: invalid bib: %w", line+2, err)
+			}
+		}
+		competitors = append(competitors, model.Competitor{
+			ID:        id,
+			Bib:       bib,
+			Name:      field(record, "name"),
+			Nation:    field(record, "nation"),
+			Club:      field(record, "club"),
+			Gender:    field(record, "gender"),
+			Category:  field(record, "category"),
+			StartTime: field(record, "starttime"),
+		})
+	}
+	return competitors, nil
+}
+
+// Save writes start list as CSV or JSON depending on the file extension
+func Save(path string, competitors []model.Competitor) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		data, err = formatCSV(competitors)
+	case ".json":
+		data, err = json.MarshalIndent(competitors, "", "  ")
+	default:
+		err = errUnknownFormat
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func formatCSV(competitors []model.Competitor) ([]byte, error) {
+	buf := &strings.Builder{}
+	if err := WriteCSV(buf, competitors); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
+
+// WriteCSV writes start list as CSV with a header row
+func WriteCSV(w io.Writer, competitors []model.Competitor) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, c := range competitors {
+		var bib string
+		if c.Bib != 0 {
+			bib = strconv.Itoa(c.Bib)
+		}
+		record := []string{strconv.Itoa(c.ID), bib, c.Name, c.Nation, c.Club, c.Gender, c.Category, c.StartTime}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func validate(competitors []model.Competitor) error {
+	ids := map[int]bool{}
+	bibs := map[int]bool{}
+	for _, c := range competitors {
+		if ids[c.ID] {
+			return fmt.Errorf("%w: %d", errDuplicateID, c.ID)
+		}
+		ids[c.ID] = true
+		if c.Bib == 0 {
+			continue
+		}
+		if bibs[c.Bib] {
+			return fmt.Errorf("%w: %d", errDuplicateBib, c.Bib)
+		}
+		bibs[c.Bib] = true
+	}
+	return nil
+}
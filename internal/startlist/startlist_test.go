@@ -0,0 +1,92 @@
+package startlist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"racingMetrics/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	want := []model.Competitor{
+		{ID: 1, Bib: 11, Name: "Anna Ivanova", Nation: "RUS", Gender: "W", Category: "W-SEN"},
+		{ID: 2, Name: "Olga Petrova"},
+	}
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"start.csv", "name, id, bib, nation, gender, category\nAnna Ivanova,1,11,RUS,W,W-SEN\nOlga Petrova,2,,,,\n"},
+		{"start.json", `[{"id":1,"bib":11,"name":"Anna Ivanova","nation":"RUS","gender":"W","category":"W-SEN"},{"id":2,"name":"Olga Petrova"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(writeFile(t, tt.name, tt.content))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	competitors := []model.Competitor{
+		{ID: 2, Bib: 12, Name: "Petrova, Olga", Category: "W-JUN", StartTime: "10:00:00.000"},
+		{ID: 1, Name: "Anna Ivanova", StartTime: "10:00:30.000"},
+	}
+
+	for _, name := range []string{"drawn.csv", "drawn.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := Save(path, competitors); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, competitors) {
+				t.Errorf("Expected %v, got %v", competitors, got)
+			}
+		})
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected error
+	}{
+		{"start.txt", "1", errUnknownFormat},
+		{"start.csv", "bib,name\n11,Anna\n", errNoIDColumn},
+		{"start.csv", "id,bib\n1,11\n1,12\n", errDuplicateID},
+		{"start.json", `[{"id":1,"bib":11},{"id":2,"bib":11}]`, errDuplicateBib},
+		{"start.csv", "", errEmpty},
+		{"start.csv", "id,bib,name\n", errEmpty},
+		{"start.json", "[]", errEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected.Error(), func(t *testing.T) {
+			_, err := Load(writeFile(t, tt.name, tt.content))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}